@@ -0,0 +1,104 @@
+package diagnose
+
+import (
+	"fmt"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+)
+
+// ALLUXIO_MASTER_QUORUM
+// Several Alluxio masters replicate their embedded journal with Raft and serve only
+// while a majority is up. An even count tolerates no more failures than one master
+// fewer, and a split into halves leaves neither side serving.
+type AlluxioMasterQuorumRule struct{}
+
+func (r *AlluxioMasterQuorumRule) ID() string { return "ALLUXIO_MASTER_QUORUM" }
+
+func (r *AlluxioMasterQuorumRule) RuntimeTypes() []string { return []string{"AlluxioRuntime"} }
+
+func (r *AlluxioMasterQuorumRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Even number of Alluxio masters",
+		Description: "AlluxioRuntime only: more than one master, but an even number; the embedded journal needs a majority and gains no fault tolerance from the last master.",
+		Severity:    "Warning",
+		Component:   "Runtime/Master",
+		Checks:      "spec.master.replicas of the AlluxioRuntime, or the master StatefulSet's replicas when the spec does not declare them.",
+		Causes:      []string{"Scaling masters from 1 to 2 for high availability"},
+		Remediation: []string{"Set spec.master.replicas to 3 or 5"},
+		Related:     []string{"MASTER_NOT_READY"},
+	}
+}
+
+func (r *AlluxioMasterQuorumRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	if g.Runtime == nil || g.Runtime.Master == nil {
+		return nil
+	}
+	master := g.Runtime.Master
+	replicas := master.Replicas
+	if master.Desired != nil && master.Desired.Replicas != nil {
+		replicas = *master.Desired.Replicas
+	}
+	if replicas < 2 || replicas%2 != 0 {
+		return nil
+	}
+
+	return []types.FailureHint{{
+		ID:         r.ID(),
+		Severity:   types.SeverityWarning,
+		Component:  "Runtime/Master",
+		Evidence:   factEvidence("AlluxioRuntime", g.Runtime.Name, "Master replicas: {replicas}; the embedded journal serves while {quorum} are up", fact("replicas", replicas), fact("quorum", replicas/2+1)),
+		Suggestion: fmt.Sprintf("Run an odd number of masters: %d masters tolerate as many failures as %d.", replicas, replicas-1),
+		Remediation: []types.RemediationStep{
+			{Description: "Read the master StatefulSet's status and events", Command: fmt.Sprintf("kubectl describe statefulset %s%s", master.Name, namespaceFlag(g))},
+			{
+				Description: "Add a master for an odd count",
+				Patch: &types.Patch{
+					Kind:      "AlluxioRuntime",
+					Name:      g.Runtime.Name,
+					Namespace: g.Dataset.Namespace,
+					YAML:      fmt.Sprintf("spec:\n  master:\n    replicas: %d\n", replicas+1),
+				},
+			},
+		},
+	}}
+}
+
+// alluxioUFSCredentialKeys are the mount options Alluxio reads the credentials of an
+// under storage from, by scheme, set through encryptOptions.
+var alluxioUFSCredentialKeys = map[string][]string{
+	"s3":  {"aws.accessKeyId", "aws.secretKey"},
+	"oss": {"fs.oss.accessKeyId", "fs.oss.accessKeySecret"},
+}
+
+// ALLUXIO_UFS_CREDENTIALS_INVALID
+// Alluxio mounts s3:// and oss:// buckets with the AccessKey pair of the mount's
+// options; the master reads them when the Dataset is mounted, so a missing Secret
+// leaves the Dataset unbound.
+type AlluxioUFSCredentialsInvalidRule struct{}
+
+func (r *AlluxioUFSCredentialsInvalidRule) ID() string { return "ALLUXIO_UFS_CREDENTIALS_INVALID" }
+
+func (r *AlluxioUFSCredentialsInvalidRule) RuntimeTypes() []string { return []string{"AlluxioRuntime"} }
+
+func (r *AlluxioUFSCredentialsInvalidRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Alluxio under storage credentials invalid",
+		Description: "AlluxioRuntime only: an s3:// or oss:// mount's credential Secret or key is missing or its AccessKey pair is incomplete (Critical), or its AccessKey is a plain option (Warning).",
+		Severity:    "Critical/Warning",
+		Component:   "Dataset",
+		Checks:      "The options and encryptOptions aws.accessKeyId and aws.secretKey of each s3:// mount, fs.oss.accessKeyId and fs.oss.accessKeySecret of each oss:// mount, and the Secret keys they read from. Only key names are read, never values.",
+		Causes:      []string{"The AccessKey Secret is missing or in another namespace", "Only one key of the pair set, e.g. after copying a mount from another Dataset", "The AccessKey given in options"},
+		Remediation: []string{"kubectl describe secret <secret> -n <ns>", "Set both keys of the pair through encryptOptions"},
+		Related:     []string{"DATASET_NOT_BOUND", "LOG_UFS_MOUNT_FAILED"},
+	}
+}
+
+func (r *AlluxioUFSCredentialsInvalidRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	var hints []types.FailureHint
+	for _, scheme := range []string{"s3", "oss"} {
+		for _, m := range mountsWithScheme(g, scheme) {
+			hints = append(hints, credentialHints(g, r.ID(), m, alluxioUFSCredentialKeys[scheme])...)
+		}
+	}
+	return hints
+}
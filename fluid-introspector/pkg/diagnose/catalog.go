@@ -0,0 +1,64 @@
+package diagnose
+
+import "strings"
+
+// Metadata documents a rule in the rule catalog. `fluidctl rules` and the README
+// rule table are generated from it.
+type Metadata struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Severity    string   `json:"severity"`  // Severities the rule reports, e.g. "Critical/Warning"
+	Component   string   `json:"component"` // Component of its findings
+	Checks      string   `json:"checks"`    // What the rule inspects in the graph
+	Causes      []string `json:"causes,omitempty"`
+	Remediation []string `json:"remediation,omitempty"`
+	Related     []string `json:"related,omitempty"`  // IDs of rules worth checking alongside
+	CausedBy    []string `json:"causedBy,omitempty"` // Declared causes, see CausalRule
+	Fleet       bool     `json:"fleet,omitempty"`    // Compares Datasets, see ClusterRule
+	Runtimes    []string `json:"runtimes,omitempty"` // Runtime types of a runtime pack rule, see RuntimeRule
+}
+
+// Documented is implemented by rules that describe themselves in the catalog.
+type Documented interface {
+	Rule
+	Metadata() Metadata
+}
+
+// Describe returns the catalog entry of a rule. Its ID, declared causes, fleet scope
+// and runtime types come from the rule itself; rules without metadata are described
+// by those only.
+func Describe(rule Rule) Metadata {
+	var m Metadata
+	if d, ok := rule.(Documented); ok {
+		m = d.Metadata()
+	}
+	m.ID = rule.ID()
+	if cr, ok := rule.(CausalRule); ok {
+		m.CausedBy = cr.CausedBy()
+	}
+	_, m.Fleet = rule.(ClusterRule)
+	if rr, ok := rule.(RuntimeRule); ok {
+		m.Runtimes = rr.RuntimeTypes()
+	}
+	return m
+}
+
+// Catalog describes the rules of the set, in order.
+func Catalog(rules RuleSet) []Metadata {
+	catalog := make([]Metadata, 0, len(rules))
+	for _, r := range rules {
+		catalog = append(catalog, Describe(r))
+	}
+	return catalog
+}
+
+// MarkdownTable renders the catalog as the rule table of the README.
+func MarkdownTable(catalog []Metadata) string {
+	var b strings.Builder
+	b.WriteString("| ID | Severity | Description |\n| :--- | :--- | :--- |\n")
+	for _, m := range catalog {
+		b.WriteString("| `" + m.ID + "` | " + m.Severity + " | " + strings.ReplaceAll(m.Description, "|", "\\|") + " |\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package diagnose_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/diagnose"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog_BuiltinRulesDocumented(t *testing.T) {
+	set, err := diagnose.DefaultRegistry.RuleSet()
+	require.NoError(t, err)
+
+	ids := make(map[string]bool)
+	for _, r := range set {
+		ids[r.ID()] = true
+	}
+	for _, r := range set {
+		_, ok := r.(diagnose.Documented)
+		require.True(t, ok, "%s has no metadata", r.ID())
+
+		m := diagnose.Describe(r)
+		assert.Equal(t, r.ID(), m.ID)
+		assert.NotEmpty(t, m.Title, r.ID())
+		assert.NotEmpty(t, m.Description, r.ID())
+		assert.NotEmpty(t, m.Severity, r.ID())
+		assert.NotEmpty(t, m.Component, r.ID())
+		assert.NotEmpty(t, m.Checks, r.ID())
+		assert.NotEmpty(t, m.Remediation, r.ID())
+		for _, related := range m.Related {
+			assert.True(t, ids[related], "%s relates to unknown rule %s", r.ID(), related)
+		}
+	}
+
+	fuse := diagnose.Describe(&diagnose.FuseMissingRule{})
+	assert.Equal(t, []string{"MASTER_NOT_READY", "THIN_FUSE_IMAGE_MISSING"}, fuse.CausedBy)
+	assert.True(t, diagnose.Describe(&diagnose.HostPortConflictRule{}).Fleet)
+}
+
+func TestCatalog_DeclarativeRule(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+rules:
+  - id: WORKER_NOT_REDUNDANT
+    title: Single worker
+    severity: Warning
+    component: Runtime/Worker
+    condition: graph.runtime.worker.replicas < 2
+    evidence:
+      kind: StatefulSet
+      name: "{{ .runtime.worker.name }}"
+    suggestion: Run at least two workers.
+`), 0o644))
+	rules, err := diagnose.LoadRules(path)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+
+	m := diagnose.Describe(rules[0])
+	assert.Equal(t, "Single worker", m.Title)
+	assert.Equal(t, "Warning", m.Severity)
+	assert.Equal(t, []string{"Run at least two workers."}, m.Remediation)
+}
+
+// The README rule table is generated from the catalog; regenerate it with
+// `fluidctl rules list -o markdown`.
+func TestCatalog_READMETableInSync(t *testing.T) {
+	readme, err := os.ReadFile(filepath.Join("..", "..", "..", "README.md"))
+	require.NoError(t, err)
+	set, err := diagnose.DefaultRegistry.RuleSet()
+	require.NoError(t, err)
+
+	table := diagnose.MarkdownTable(diagnose.Catalog(set))
+	assert.True(t, strings.Contains(string(readme), table), "README rule table is out of date:\n%s", table)
+}
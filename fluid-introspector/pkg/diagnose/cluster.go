@@ -0,0 +1,206 @@
+package diagnose
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+)
+
+// ClusterRule is implemented by rules that compare a Dataset with the other Datasets
+// of the fleet, e.g. runtimes colliding on a node. Fleet diagnosis calls EvaluateCluster;
+// single-Dataset diagnosis calls Evaluate, which sees no peers.
+type ClusterRule interface {
+	Rule
+	// EvaluateCluster reports the findings for g given every graph of the fleet, g included.
+	EvaluateCluster(g *types.ResourceGraph, fleet []*types.ResourceGraph) []types.FailureHint
+}
+
+// DiagnoseFleet diagnoses each graph, in order, with cluster rules comparing it to the
+// others. A nil profile applies the rules unchanged. Options apply to every graph.
+func DiagnoseFleet(graphs []*types.ResourceGraph, rules RuleSet, profile *Profile, opts ...Option) ([]*types.DiagnosticResult, error) {
+	opts = append([]Option{WithRuleSet(rules), WithProfile(profile)}, opts...)
+	opts = append(opts, withFleet(graphs))
+
+	results := make([]*types.DiagnosticResult, 0, len(graphs))
+	for _, g := range graphs {
+		result, err := Run(g, opts...)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// HOST_PORT_CONFLICT
+// Reports runtime pods sharing a host port with a pod of another Dataset on the same
+// node. Workers and fuse pods of most runtimes use the host network, so ports of two
+// runtimes on one node collide; the later pod fails to bind or cannot be scheduled.
+type HostPortConflictRule struct{}
+
+func (r *HostPortConflictRule) ID() string { return "HOST_PORT_CONFLICT" }
+
+func (r *HostPortConflictRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Host port conflict",
+		Description: "A runtime pod shares a host port with another Dataset's runtime pod on the same node (fleet only).",
+		Severity:    "Critical/Warning",
+		Component:   "Runtime pods",
+		Checks:      "Host ports of runtime pods against those of other Datasets' pods on the same node.",
+		Causes:      []string{"Two hostNetwork runtimes with the same default ports on one node"},
+		Remediation: []string{"Change the ports of one runtime in its spec", "Keep the Datasets apart with spec.nodeAffinity"},
+		Related:     []string{"POD_UNSCHEDULABLE", "HOST_PATH_CONFLICT"},
+	}
+}
+
+func (r *HostPortConflictRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	return r.EvaluateCluster(g, []*types.ResourceGraph{g})
+}
+
+func (r *HostPortConflictRule) EvaluateCluster(g *types.ResourceGraph, fleet []*types.ResourceGraph) []types.FailureHint {
+	var hints []types.FailureHint
+	forEachPod(g, func(rc runtimeComponent, p types.PodInfo) {
+		if p.Node == "" || len(p.HostPorts) == 0 {
+			return
+		}
+		for _, peer := range peerPods(g, fleet, p.Node) {
+			shared := sharedPorts(p.HostPorts, peer.pod.HostPorts)
+			if len(shared) == 0 {
+				continue
+			}
+			label := "Host port"
+			if len(shared) > 1 {
+				label = "Host ports"
+			}
+			hints = append(hints, peer.hint(podHint(r.ID(), rc, p, label+" {ports} on node {node} also used by {dataset} (pod {peer})",
+				"Two runtimes on one node must not share host ports. Change the ports of one runtime in its spec, or keep the Datasets apart with spec.nodeAffinity.",
+				fact("ports", strings.Join(shared, ", ")), fact("node", p.Node), fact("dataset", peer.dataset), fact("peer", peer.pod.Name))))
+		}
+	})
+	return hints
+}
+
+// HOST_PATH_CONFLICT
+// Reports runtime pods whose hostPath directories, such as tiered store cache paths,
+// are the same as or nested in a directory of another Dataset's pod on the same node.
+// The runtimes then evict each other's cache blocks and overrun their quotas.
+type HostPathConflictRule struct{}
+
+func (r *HostPathConflictRule) ID() string { return "HOST_PATH_CONFLICT" }
+
+func (r *HostPathConflictRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Host path conflict",
+		Description: "A runtime pod's hostPath cache directory is the same as, or nested in, another Dataset's on the same node (fleet only).",
+		Severity:    "Critical/Warning",
+		Component:   "Runtime pods",
+		Checks:      "hostPath volumes of runtime pods, system directories excluded, against those of other Datasets' pods on the same node.",
+		Causes:      []string{"Tiered store levels of two runtimes pointing at the same directory"},
+		Remediation: []string{"Give each runtime its own directory in spec.tieredstore.levels[].path"},
+		Related:     []string{"HOST_PORT_CONFLICT"},
+	}
+}
+
+func (r *HostPathConflictRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	return r.EvaluateCluster(g, []*types.ResourceGraph{g})
+}
+
+func (r *HostPathConflictRule) EvaluateCluster(g *types.ResourceGraph, fleet []*types.ResourceGraph) []types.FailureHint {
+	var hints []types.FailureHint
+	forEachPod(g, func(rc runtimeComponent, p types.PodInfo) {
+		if p.Node == "" {
+			return
+		}
+		for _, peer := range peerPods(g, fleet, p.Node) {
+			for _, mine := range cachePaths(p.HostPaths) {
+				for _, theirs := range cachePaths(peer.pod.HostPaths) {
+					if !pathsOverlap(mine, theirs) {
+						continue
+					}
+					hints = append(hints, peer.hint(podHint(r.ID(), rc, p, "Host path {path} on node {node} overlaps {peerPath} used by {dataset} (pod {peer})",
+						"Give each runtime its own cache directory in spec.tieredstore.levels[].path, e.g. suffixed with the Dataset's namespace and name.",
+						fact("path", mine), fact("node", p.Node), fact("peerPath", theirs), fact("dataset", peer.dataset), fact("peer", peer.pod.Name))))
+				}
+			}
+		}
+	})
+	return hints
+}
+
+// peerPod is a runtime pod of another Dataset of the fleet.
+type peerPod struct {
+	dataset   string // namespace/name
+	namespace string
+	pod       types.PodInfo
+}
+
+// hint adds the peer pod to the objects of a finding about a pod colliding with it.
+func (peer peerPod) hint(h types.FailureHint) types.FailureHint {
+	h.Evidence.Objects = []types.ObjectRef{ref(h.Evidence.Kind, h.Evidence.Name), podRef(peer.namespace, peer.pod)}
+	return h
+}
+
+// peerPods lists the runtime pods of the other Datasets of the fleet running on node.
+func peerPods(g *types.ResourceGraph, fleet []*types.ResourceGraph, node string) []peerPod {
+	var peers []peerPod
+	for _, other := range fleet {
+		if other == nil || other.Dataset == nil || sameDataset(g, other) {
+			continue
+		}
+		forEachPod(other, func(_ runtimeComponent, p types.PodInfo) {
+			if p.Node == node {
+				peers = append(peers, peerPod{dataset: datasetKey(other), namespace: other.Dataset.Namespace, pod: p})
+			}
+		})
+	}
+	return peers
+}
+
+func sameDataset(a, b *types.ResourceGraph) bool {
+	return a == b || datasetKey(a) == datasetKey(b)
+}
+
+func datasetKey(g *types.ResourceGraph) string {
+	return g.Dataset.Namespace + "/" + g.Dataset.Name
+}
+
+func sharedPorts(a, b []int32) []string {
+	theirs := make(map[int32]bool, len(b))
+	for _, p := range b {
+		theirs[p] = true
+	}
+	var shared []string
+	for _, p := range a {
+		if theirs[p] {
+			shared = append(shared, fmt.Sprint(p))
+		}
+	}
+	return shared
+}
+
+// systemHostPaths are shared by design, e.g. /dev/fuse for every fuse pod. Of /dev
+// only the devices are: /dev/shm holds MEM tiered stores.
+var systemHostPaths = []string{"/dev/fuse", "/dev/null", "/etc", "/lib", "/proc", "/run", "/sys", "/usr", "/var/lib/kubelet", "/var/log", "/var/run"}
+
+// cachePaths drops system directories from hostPath volume paths.
+func cachePaths(paths []string) []string {
+	var out []string
+	for _, p := range paths {
+		system := p == "/"
+		for _, sys := range systemHostPaths {
+			system = system || p == sys || strings.HasPrefix(p, sys+"/")
+		}
+		if !system {
+			out = append(out, p)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// pathsOverlap reports whether a and b are the same directory or one contains the other.
+func pathsOverlap(a, b string) bool {
+	return a == b || strings.HasPrefix(a, strings.TrimSuffix(b, "/")+"/") || strings.HasPrefix(b, strings.TrimSuffix(a, "/")+"/")
+}
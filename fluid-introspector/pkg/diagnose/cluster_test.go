@@ -0,0 +1,78 @@
+package diagnose_test
+
+import (
+	"testing"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/diagnose"
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func workerGraph(namespace, name string, pods ...types.PodInfo) *types.ResourceGraph {
+	return &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Name: name, Namespace: namespace, Status: "Bound"},
+		Runtime: &types.RuntimeInfo{
+			Name:   name,
+			Worker: &types.ComponentInfo{Name: name + "-worker", Replicas: int32(len(pods)), Ready: int32(len(pods)), Pods: pods},
+		},
+	}
+}
+
+func TestDiagnoseFleet_HostConflicts(t *testing.T) {
+	a := workerGraph("team-a", "imagenet",
+		types.PodInfo{Name: "imagenet-worker-0", Ready: true, Node: "node-1", HostPorts: []int32{29999, 30000}, HostPaths: []string{"/dev/fuse", "/mnt/ssd/imagenet"}},
+	)
+	b := workerGraph("team-b", "coco",
+		types.PodInfo{Name: "coco-worker-0", Ready: true, Node: "node-1", HostPorts: []int32{30000, 30001}, HostPaths: []string{"/dev/fuse", "/mnt/ssd"}},
+	)
+	c := workerGraph("team-c", "logs",
+		// Same ports and path as imagenet, but on another node.
+		types.PodInfo{Name: "logs-worker-0", Ready: true, Node: "node-2", HostPorts: []int32{29999}, HostPaths: []string{"/mnt/ssd/imagenet"}},
+	)
+
+	results, err := diagnose.DiagnoseFleet([]*types.ResourceGraph{a, b, c}, diagnose.RuleSet{&diagnose.HostPortConflictRule{}, &diagnose.HostPathConflictRule{}}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	ids := func(r *types.DiagnosticResult) []string {
+		var out []string
+		for _, h := range r.FailureHints {
+			out = append(out, h.ID)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"HOST_PATH_CONFLICT", "HOST_PORT_CONFLICT"}, ids(results[0]))
+	assert.Equal(t, []string{"HOST_PATH_CONFLICT", "HOST_PORT_CONFLICT"}, ids(results[1]))
+	assert.True(t, results[2].IsHealthy)
+
+	port := results[0].FailureHints[1]
+	assert.Equal(t, "imagenet-worker-0", port.Evidence.Name)
+	assert.Equal(t, "Host port 30000 on node node-1 also used by team-b/coco (pod coco-worker-0)", port.Evidence.Detail)
+	// /dev/fuse is shared by design and not reported.
+	assert.Contains(t, results[0].FailureHints[0].Evidence.Detail, "/mnt/ssd/imagenet")
+
+	// Without the fleet there are no peers to collide with, and the trace says so.
+	single, err := diagnose.Run(a, diagnose.WithRuleSet(diagnose.RuleSet{&diagnose.HostPortConflictRule{}}), diagnose.WithTrace())
+	require.NoError(t, err)
+	assert.True(t, single.IsHealthy)
+	require.Len(t, single.Trace, 1)
+	assert.Equal(t, types.TraceSkipped, single.Trace[0].Outcome)
+	assert.Contains(t, single.Trace[0].Reason, "Fleet only")
+}
+
+func TestDiagnoseFleet_SharedMemoryCache(t *testing.T) {
+	a := workerGraph("team-a", "imagenet",
+		types.PodInfo{Name: "imagenet-worker-0", Ready: true, Node: "node-1", HostPaths: []string{"/dev/fuse", "/dev/shm/cache"}},
+	)
+	b := workerGraph("team-b", "coco",
+		types.PodInfo{Name: "coco-worker-0", Ready: true, Node: "node-1", HostPaths: []string{"/dev/fuse", "/dev/shm"}},
+	)
+
+	results, err := diagnose.DiagnoseFleet([]*types.ResourceGraph{a, b}, diagnose.RuleSet{&diagnose.HostPathConflictRule{}}, nil)
+	require.NoError(t, err)
+	require.Len(t, results[0].FailureHints, 1)
+	// The MEM tiered stores collide; the shared fuse device does not.
+	assert.Contains(t, results[0].FailureHints[0].Evidence.Detail, "/dev/shm/cache")
+	assert.NotContains(t, results[0].FailureHints[0].Evidence.Detail, "/dev/fuse")
+}
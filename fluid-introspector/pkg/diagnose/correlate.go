@@ -0,0 +1,143 @@
+package diagnose
+
+import (
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+)
+
+// CausalRule is implemented by rules whose findings are typically a consequence
+// of other rules firing. When the missing Runtime explains the unbound Dataset,
+// the Dataset finding links to it instead of competing with it as a root cause.
+type CausalRule interface {
+	Rule
+	// CausedBy lists the IDs of rules whose findings explain this rule's findings.
+	CausedBy() []string
+}
+
+// causalGraph collects the declared causes of every rule, keyed by rule ID.
+func causalGraph(rs []Rule) map[string][]string {
+	causes := make(map[string][]string)
+	for _, r := range rs {
+		if cr, ok := r.(CausalRule); ok {
+			causes[r.ID()] = cr.CausedBy()
+		}
+	}
+	return causes
+}
+
+// correlate links each finding to the fired findings that cause it and marks
+// the findings no other finding explains as root causes. A finding of a declared
+// cause only explains the findings it is about, see explains. Hints must already
+// be sorted; findings that explain each other in a cycle no root cause leads to get
+// the first (most severe) of them promoted, so every finding is a root cause or
+// explained by one.
+func correlate(g *types.ResourceGraph, hints []types.FailureHint, causes map[string][]string) {
+	byID := make(map[string][]int, len(hints))
+	for i, h := range hints {
+		byID[h.ID] = append(byID[h.ID], i)
+	}
+
+	// explained[j] lists the findings the finding j explains.
+	explained := make([][]int, len(hints))
+	for i := range hints {
+		for _, c := range causes[hints[i].ID] {
+			if c == hints[i].ID {
+				continue
+			}
+			linked := false
+			for _, j := range byID[c] {
+				if explains(g, hints[j], hints[i]) {
+					explained[j] = append(explained[j], i)
+					linked = true
+				}
+			}
+			if linked {
+				hints[i].CausedBy = append(hints[i].CausedBy, c)
+			}
+		}
+		hints[i].RootCause = len(hints[i].CausedBy) == 0
+	}
+
+	reached := make([]bool, len(hints))
+	var reach func(i int)
+	reach = func(i int) {
+		if reached[i] {
+			return
+		}
+		reached[i] = true
+		for _, j := range explained[i] {
+			reach(j)
+		}
+	}
+	for i := range hints {
+		if hints[i].RootCause {
+			reach(i)
+		}
+	}
+	for i := range hints {
+		if !reached[i] {
+			hints[i].RootCause = true
+			reach(i)
+		}
+	}
+}
+
+// explains reports whether the cause finding c explains the finding h. Findings that
+// locate no pod, node or workload are about the Dataset as a whole: they explain any
+// finding, or any of their component, e.g. the MEM quota every worker caches, and are
+// explained by any cause. Otherwise c must be about the same pod, the node of h's pod
+// or a pod on h's node, or the workload of another component: an unready master
+// explains the unready workers.
+func explains(g *types.ResourceGraph, c, h types.FailureHint) bool {
+	causes, effects := located(c), located(h)
+	if len(causes) == 0 {
+		return !isRuntimeComponent(c.Component) || c.Component == h.Component
+	}
+	if len(effects) == 0 {
+		return true
+	}
+	for _, co := range causes {
+		if (co.Kind == "StatefulSet" || co.Kind == "DaemonSet") && c.Component != h.Component {
+			return true
+		}
+		for _, eo := range effects {
+			if sameObject(co, eo) || onNode(g, eo, co) || onNode(g, co, eo) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// located returns the objects of a finding's evidence that place it on part of the
+// Dataset: pods, nodes and component workloads.
+func located(h types.FailureHint) []types.ObjectRef {
+	var out []types.ObjectRef
+	for _, o := range h.Evidence.Objects {
+		switch o.Kind {
+		case "Pod", "Node", "StatefulSet", "DaemonSet":
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+func sameObject(a, b types.ObjectRef) bool {
+	return a.Kind == b.Kind && a.Namespace == b.Namespace && a.Name == b.Name
+}
+
+// onNode reports whether pod is a runtime pod of the graph running on node.
+func onNode(g *types.ResourceGraph, pod, node types.ObjectRef) bool {
+	if pod.Kind != "Pod" || node.Kind != "Node" || pod.Namespace != g.Dataset.Namespace {
+		return false
+	}
+	p, ok := findPod(g, pod.Name)
+	return ok && p.Node == node.Name
+}
+
+func isRuntimeComponent(component string) bool {
+	switch component {
+	case "Runtime/Master", "Runtime/Worker", "Runtime/Fuse":
+		return true
+	}
+	return false
+}
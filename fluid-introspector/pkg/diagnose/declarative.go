@@ -0,0 +1,285 @@
+package diagnose
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	"github.com/google/cel-go/cel"
+	"sigs.k8s.io/yaml"
+)
+
+// RuleFile is the on-disk format for declarative rules:
+//
+//	rules:
+//	  - id: WORKER_NOT_REDUNDANT
+//	    severity: Warning
+//	    component: Runtime/Worker
+//	    condition: has(graph.runtime) && has(graph.runtime.worker) && graph.runtime.worker.replicas < 2
+//	    evidence:
+//	      kind: StatefulSet
+//	      name: "{{ .runtime.worker.name }}"
+//	      detail: "Replicas: {{ .runtime.worker.replicas }}"
+//	    suggestion: Run at least two workers so cached data survives a node failure.
+//
+// The condition is a CEL expression over the JSON form of the ResourceGraph, bound
+// to the variable `graph`. Evidence fields are Go templates over the same document.
+// A file may also declare log signatures, see LogSignature.
+type RuleFile struct {
+	Rules      []RuleSpec     `json:"rules"`
+	Signatures []LogSignature `json:"signatures,omitempty"`
+}
+
+// RuleSpec declares a single rule.
+type RuleSpec struct {
+	ID          string              `json:"id"`
+	Title       string              `json:"title,omitempty"`       // Optional, shown by `fluidctl rules`
+	Description string              `json:"description,omitempty"` // Optional, shown by `fluidctl rules`
+	Severity    types.SeverityLevel `json:"severity"`
+	Component   string              `json:"component"`
+	Condition   string              `json:"condition"`
+	Evidence    EvidenceSpec        `json:"evidence"`
+	Suggestion  string              `json:"suggestion"`
+	CausedBy    []string            `json:"causedBy,omitempty"` // Optional, see CausalRule
+}
+
+// EvidenceSpec holds the templates rendered into a finding's Evidence.
+type EvidenceSpec struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Detail string `json:"detail"`
+}
+
+var ruleIDPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// celEnv declares the single `graph` variable available to conditions.
+var celEnv = func() *cel.Env {
+	env, err := cel.NewEnv(cel.Variable("graph", cel.MapType(cel.StringType, cel.DynType)))
+	if err != nil {
+		panic(err)
+	}
+	return env
+}()
+
+// DeclarativeRule is a Rule compiled from a RuleSpec.
+type DeclarativeRule struct {
+	spec     RuleSpec
+	program  cel.Program
+	evidence [3]*template.Template // Kind, Name, Detail
+}
+
+// NewDeclarativeRule validates and compiles a RuleSpec.
+func NewDeclarativeRule(spec RuleSpec) (*DeclarativeRule, error) {
+	if !ruleIDPattern.MatchString(spec.ID) {
+		return nil, fmt.Errorf("rule id %q must be UPPER_SNAKE_CASE", spec.ID)
+	}
+	if severityRank(spec.Severity) == 0 {
+		return nil, fmt.Errorf("rule %s: severity %q must be one of Critical, Warning, Info", spec.ID, spec.Severity)
+	}
+	if spec.Component == "" {
+		return nil, fmt.Errorf("rule %s: component is required", spec.ID)
+	}
+	if strings.TrimSpace(spec.Condition) == "" {
+		return nil, fmt.Errorf("rule %s: condition is required", spec.ID)
+	}
+
+	ast, iss := celEnv.Compile(spec.Condition)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("rule %s: invalid condition: %w", spec.ID, iss.Err())
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("rule %s: condition must evaluate to bool, got %s", spec.ID, ast.OutputType())
+	}
+	program, err := celEnv.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: %w", spec.ID, err)
+	}
+
+	r := &DeclarativeRule{spec: spec, program: program}
+	for i, text := range []string{spec.Evidence.Kind, spec.Evidence.Name, spec.Evidence.Detail} {
+		tmpl, err := template.New(spec.ID).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: invalid evidence template: %w", spec.ID, err)
+		}
+		r.evidence[i] = tmpl
+	}
+	return r, nil
+}
+
+func (r *DeclarativeRule) ID() string { return r.spec.ID }
+
+func (r *DeclarativeRule) CausedBy() []string { return r.spec.CausedBy }
+
+func (r *DeclarativeRule) Metadata() Metadata {
+	m := Metadata{
+		Title:       r.spec.Title,
+		Description: r.spec.Description,
+		Severity:    string(r.spec.Severity),
+		Component:   r.spec.Component,
+		Checks:      "CEL condition: " + r.spec.Condition,
+	}
+	if r.spec.Suggestion != "" {
+		m.Remediation = []string{r.spec.Suggestion}
+	}
+	return m
+}
+
+// Evaluate runs the CEL condition against the graph. Evaluation errors, such as
+// accessing an absent field without has(), are reported as an ENGINE_RULE_ERROR
+// finding: the condition could not tell whether the rule matches. So are evidence
+// templates failing to render, e.g. reading a misspelled or absent field.
+func (r *DeclarativeRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	return r.evaluateDocument(graphDocument(g))
+}
+
+func (r *DeclarativeRule) evaluateDocument(doc map[string]interface{}, err error) []types.FailureHint {
+	if err != nil {
+		return []types.FailureHint{ruleErrorHint(r.spec.ID, fmt.Sprintf("Graph document: %v", err),
+			"The graph could not be converted to the document conditions read. Report it together with the graph (-o json).")}
+	}
+	out, _, err := r.program.Eval(map[string]interface{}{"graph": doc})
+	if err != nil {
+		return []types.FailureHint{ruleErrorHint(r.spec.ID, fmt.Sprintf("Condition failed: %v", err),
+			"Fix the rule's condition, e.g. guard optional fields with has(): has(graph.runtime) && graph.runtime.master.ready < 1")}
+	}
+	if matched, ok := out.Value().(bool); !ok || !matched {
+		return nil
+	}
+
+	var evidence [3]string
+	for i, tmpl := range r.evidence {
+		if evidence[i], err = render(tmpl, doc); err != nil {
+			return []types.FailureHint{ruleErrorHint(r.spec.ID, fmt.Sprintf("Evidence failed: %v", err),
+				"Fix the rule's evidence templates: the fields they read must be present whenever the condition holds, see the graph (-o json).")}
+		}
+	}
+	return []types.FailureHint{{
+		ID:         r.spec.ID,
+		Severity:   r.spec.Severity,
+		Component:  r.spec.Component,
+		Evidence:   types.Evidence{Kind: evidence[0], Name: evidence[1], Detail: evidence[2]},
+		Suggestion: r.spec.Suggestion,
+	}}
+}
+
+// documentRule is implemented by rules evaluated against the JSON form of the graph.
+// The engine converts the graph once per run for all of them.
+type documentRule interface {
+	Rule
+	evaluateDocument(doc map[string]interface{}, err error) []types.FailureHint
+}
+
+// runDocument converts the graph of a run on first use.
+type runDocument struct {
+	done bool
+	doc  map[string]interface{}
+	err  error
+}
+
+func (d *runDocument) get(g *types.ResourceGraph) (map[string]interface{}, error) {
+	if !d.done {
+		d.doc, d.err = graphDocument(g)
+		d.done = true
+	}
+	return d.doc, d.err
+}
+
+// graphDocument converts the graph to its JSON form, the document conditions and templates see.
+func graphDocument(g *types.ResourceGraph) (map[string]interface{}, error) {
+	raw, err := json.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+	doc := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// render executes an evidence template; fields absent from the document are errors.
+func render(tmpl *template.Template, doc map[string]interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, doc); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// LoadRuleFile reads and compiles the declarative rules and log signatures of a single YAML file.
+func LoadRuleFile(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file RuleFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var loaded []Rule
+	for _, spec := range file.Rules {
+		r, err := NewDeclarativeRule(spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		loaded = append(loaded, r)
+	}
+	for _, sig := range file.Signatures {
+		r, err := NewLogSignatureRule(sig)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		loaded = append(loaded, r)
+	}
+	if _, err := NewRuleSet(loaded...); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return loaded, nil
+}
+
+// LoadRules loads declarative rules from a file, or from every *.yaml/*.yml file
+// of a directory in lexical order. Rule IDs must be unique across all files.
+func LoadRules(path string) ([]Rule, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return LoadRuleFile(path)
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if !e.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			files = append(files, filepath.Join(path, e.Name()))
+		}
+	}
+	sort.Strings(files)
+
+	var loaded []Rule
+	for _, f := range files {
+		rs, err := LoadRuleFile(f)
+		if err != nil {
+			return nil, err
+		}
+		loaded = append(loaded, rs...)
+	}
+	if _, err := NewRuleSet(loaded...); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return loaded, nil
+}
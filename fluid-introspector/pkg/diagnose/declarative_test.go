@@ -0,0 +1,158 @@
+package diagnose_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/diagnose"
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redundancyRules = `
+rules:
+  - id: WORKER_NOT_REDUNDANT
+    severity: Warning
+    component: Runtime/Worker
+    condition: has(graph.runtime) && has(graph.runtime.worker) && graph.runtime.worker.replicas < 2
+    evidence:
+      kind: StatefulSet
+      name: "{{ .runtime.worker.name }}"
+      detail: "Replicas: {{ .runtime.worker.replicas }}"
+    suggestion: Run at least two workers.
+`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestDeclarativeRules_RunAlongsideBuiltins(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "rules.yaml", redundancyRules)
+
+	loaded, err := diagnose.LoadRules(path)
+	require.NoError(t, err)
+	builtins, err := diagnose.DefaultRegistry.RuleSet()
+	require.NoError(t, err)
+	set, err := builtins.With(loaded...)
+	require.NoError(t, err)
+
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Status: "Bound"},
+		Runtime: &types.RuntimeInfo{
+			Worker: &types.ComponentInfo{Name: "demo-data-worker", Replicas: 1, Ready: 0},
+		},
+	}
+	result := diagnose.DiagnoseWithRules(graph, set)
+
+	ids := []string{}
+	for _, h := range result.FailureHints {
+		ids = append(ids, h.ID)
+		if h.ID == "WORKER_NOT_REDUNDANT" {
+			assert.Equal(t, "demo-data-worker", h.Evidence.Name)
+			assert.Equal(t, "Replicas: 1", h.Evidence.Detail)
+		}
+	}
+	assert.ElementsMatch(t, []string{"WORKER_PARTIALLY_READY", "WORKER_NOT_REDUNDANT"}, ids)
+
+	// Absent fields do not match instead of failing the diagnosis.
+	result = diagnose.DiagnoseWithRules(&types.ResourceGraph{Dataset: &types.DatasetInfo{Status: "Bound"}}, set)
+	for _, h := range result.FailureHints {
+		assert.NotEqual(t, "WORKER_NOT_REDUNDANT", h.ID)
+	}
+}
+
+func TestDeclarativeRules_EvaluationError(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "rules.yaml", `
+rules:
+  - id: WORKER_UNGUARDED
+    severity: Warning
+    component: Runtime/Worker
+    condition: graph.runtime.worker.replicas < 2
+  - id: WORKER_GUARDED
+    severity: Warning
+    component: Runtime/Worker
+    condition: has(graph.runtime) && graph.runtime.worker.replicas < 2
+`)
+	loaded, err := diagnose.LoadRules(path)
+	require.NoError(t, err)
+
+	// Without a Runtime the unguarded condition fails: reported, not taken for "no match".
+	result, err := diagnose.Run(&types.ResourceGraph{Dataset: &types.DatasetInfo{Status: "Bound"}},
+		diagnose.WithRuleSet(loaded), diagnose.WithTrace())
+	require.NoError(t, err)
+	require.Len(t, result.FailureHints, 1)
+	assert.Equal(t, diagnose.RuleErrorID, result.FailureHints[0].ID)
+	assert.Equal(t, "WORKER_UNGUARDED", result.FailureHints[0].Evidence.Name)
+	assert.Contains(t, result.FailureHints[0].Evidence.Detail, "Condition failed")
+	require.Len(t, result.Trace, 2)
+	assert.Equal(t, types.TraceError, result.Trace[0].Outcome)
+	assert.Equal(t, types.TracePassed, result.Trace[1].Outcome)
+
+	// So is evidence reading a misspelled field, instead of rendering empty.
+	path = writeFile(t, t.TempDir(), "rules.yaml", `
+rules:
+  - id: DATASET_UNLABELED
+    severity: Info
+    component: Dataset
+    condition: "true"
+    evidence:
+      kind: Dataset
+      name: "{{ .dataset.nmae }}"
+`)
+	loaded, err = diagnose.LoadRules(path)
+	require.NoError(t, err)
+	result, err = diagnose.Run(&types.ResourceGraph{Dataset: &types.DatasetInfo{Name: "demo-data", Status: "Bound"}, Runtime: &types.RuntimeInfo{}},
+		diagnose.WithRuleSet(loaded))
+	require.NoError(t, err)
+	require.Len(t, result.FailureHints, 1)
+	assert.Equal(t, diagnose.RuleErrorID, result.FailureHints[0].ID)
+	assert.Equal(t, "DATASET_UNLABELED", result.FailureHints[0].Evidence.Name)
+	assert.Contains(t, result.FailureHints[0].Evidence.Detail, "nmae")
+}
+
+func TestDeclarativeRules_Validation(t *testing.T) {
+	cases := map[string]string{
+		"bad id":         "rules:\n  - {id: lower, severity: Info, component: X, condition: 'true'}",
+		"bad severity":   "rules:\n  - {id: A_RULE, severity: Fatal, component: X, condition: 'true'}",
+		"bad condition":  "rules:\n  - {id: A_RULE, severity: Info, component: X, condition: 'graph.'}",
+		"non-bool":       "rules:\n  - {id: A_RULE, severity: Info, component: X, condition: '1 + 1'}",
+		"bad template":   "rules:\n  - {id: A_RULE, severity: Info, component: X, condition: 'true', evidence: {name: '{{ .x'}}",
+		"unknown field":  "rules:\n  - {id: A_RULE, severity: Info, component: X, condition: 'true', when: 'x'}",
+		"duplicate rule": "rules:\n  - {id: A_RULE, severity: Info, component: X, condition: 'true'}\n  - {id: A_RULE, severity: Info, component: X, condition: 'true'}",
+	}
+	for name, content := range cases {
+		path := writeFile(t, t.TempDir(), "rules.yaml", content)
+		_, err := diagnose.LoadRules(path)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestDeclarativeRules_LoadDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "b.yaml", "rules:\n  - {id: B_RULE, severity: Info, component: X, condition: 'true'}")
+	writeFile(t, dir, "a.yml", "rules:\n  - {id: A_RULE, severity: Info, component: X, condition: 'true'}")
+	writeFile(t, dir, "notes.txt", "ignored")
+
+	loaded, err := diagnose.LoadRules(dir)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, "A_RULE", loaded[0].ID())
+	assert.Equal(t, "B_RULE", loaded[1].ID())
+
+	// IDs must be unique across files, and may not shadow built-ins.
+	writeFile(t, dir, "c.yaml", "rules:\n  - {id: A_RULE, severity: Info, component: X, condition: 'true'}")
+	_, err = diagnose.LoadRules(dir)
+	assert.Error(t, err)
+
+	builtins, _ := diagnose.DefaultRegistry.RuleSet()
+	shadow := writeFile(t, t.TempDir(), "shadow.yaml", "rules:\n  - {id: FUSE_MISSING, severity: Info, component: X, condition: 'true'}")
+	loaded, err = diagnose.LoadRules(shadow)
+	require.NoError(t, err)
+	_, err = builtins.With(loaded...)
+	assert.Error(t, err)
+}
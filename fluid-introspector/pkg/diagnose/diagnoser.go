@@ -3,58 +3,167 @@ package diagnose
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
 )
 
-// Diagnose evaluates the provided ResourceGraph against a set of registered rules.
-// It returns a deterministic DiagnosticResult.
+// Diagnose evaluates the provided ResourceGraph against the rules of the DefaultRegistry.
+// It is Run without options; use Run to fix the clock, e.g. for reproducible output.
 func Diagnose(graph *types.ResourceGraph) *types.DiagnosticResult {
+	result, _ := Run(graph) // Cannot fail without profile or context
+	return result
+}
+
+// DiagnoseWithRules evaluates the ResourceGraph against the given rule set, in its order.
+// Causal relationships are only resolved between rules of the set.
+func DiagnoseWithRules(graph *types.ResourceGraph, rules RuleSet) *types.DiagnosticResult {
+	result, _ := Run(graph, WithRuleSet(rules))
+	return result
+}
+
+// diagnoseGraph runs the pipeline on one graph, stamping the result with the clock of
+// o. With a fleet, cluster rules compare the graph to the other graphs of the fleet;
+// with logs, log rules match the sampled pod logs. Traced runs explain every rule.
+func diagnoseGraph(graph *types.ResourceGraph, rules RuleSet, o *options) (*types.DiagnosticResult, error) {
 	if graph == nil {
-		return nil
+		return nil, nil
 	}
 
 	result := &types.DiagnosticResult{
-		Timestamp:     time.Now(),
+		Timestamp:     o.now(),
 		ResourceGraph: graph,
 		IsHealthy:     true,
 	}
 
 	var allHints []types.FailureHint
 
-	// 1. Iterate Rules
-	// We use the 'rules' slice defined in rules.go, which guarantees order.
-	for _, rule := range rules {
-		// Evaluate
-		hint := rule.Evaluate(graph)
-		if hint != nil {
-			allHints = append(allHints, *hint)
-			result.IsHealthy = false
+	// 1. Validate: rules rely on the graph describing a Dataset.
+	if problems := validateGraph(graph); len(problems) > 0 {
+		for _, p := range problems {
+			allHints = append(allHints, invalidGraphHint(p))
 		}
+		result.IsHealthy = false
+		result.FailureHints = allHints
+		if o.trace {
+			for _, rule := range rules {
+				result.Trace = append(result.Trace, skippedTrace(rule, "The graph is invalid: "+strings.Join(problems, "; ")))
+			}
+		}
+		correlate(graph, allHints, nil)
+		scoreResult(result)
+		result.Fingerprint = Fingerprint(allHints)
+		result.Summary = generateSummary(false, allHints, 0)
+		return result, nil
 	}
 
-	// 2. Sort Hints for Determinism
-	// Rules are already executed in order, but we can sort by severity as requested:
-	// Severity (Critical > Warning) -> Component -> ID
-	sort.SliceStable(allHints, func(i, j int) bool {
-		hi, hj := allHints[i], allHints[j]
-		if hi.Severity != hj.Severity {
-			// e.g. "Critical" (8 chars) vs "Warning" (7 chars). Crude.
-			// Better: define numeric precedence.
-			return severityRank(hi.Severity) > severityRank(hj.Severity)
+	// 2. Iterate Rules
+	// The rule set is an ordered slice, which guarantees order.
+	doc := &runDocument{}
+	for _, rule := range rules {
+		if err := o.ctx.Err(); err != nil {
+			return nil, err
 		}
-		if hi.Component != hj.Component {
-			return hi.Component < hj.Component
+		if !appliesTo(rule, graph) {
+			// A runtime pack rule for another runtime
+			if o.trace {
+				result.Trace = append(result.Trace, notApplicableTrace(rule, graph))
+			}
+			continue
 		}
-		// Tie-breaker: Evidence Name or Detail?
-		return hi.Evidence.Name < hj.Evidence.Name
+		// Evaluate; a rule may report several findings (e.g. one per pod).
+		hints := evaluateRule(rule, graph, o.fleet, o.logs, doc)
+		o.profile.overrideSeverity(rule, hints)
+		if o.trace {
+			result.Trace = append(result.Trace, traceRule(rule, graph, hints, o.fleet, o.logs))
+		}
+		allHints = append(allHints, hints...)
+	}
+
+	// 3. Resolve the objects of the evidence, and plan remediation for findings whose rule did not.
+	resolveEvidence(graph, allHints)
+	planRemediation(graph, allHints)
+
+	// 4. Move findings acknowledged on the Dataset aside.
+	allHints, result.Silenced = applySilences(graph, allHints, result.Timestamp)
+	countSilenced(result.Trace, result.Silenced)
+	result.IsHealthy = len(allHints) == 0
+
+	// 5. Sort Hints for Determinism
+	// Rules are already executed in order, but we can sort by severity as requested:
+	// Severity (Critical > Warning) -> Component -> ID -> Evidence
+	sortHints(allHints)
+	sort.SliceStable(result.Silenced, func(i, j int) bool {
+		return hintLess(result.Silenced[i].FailureHint, result.Silenced[j].FailureHint)
 	})
 
+	// 6. Correlate: link consequences to their causes and mark root causes.
+	correlate(graph, allHints, causalGraph(rules))
+
 	result.FailureHints = allHints
-	result.Summary = generateSummary(result.IsHealthy, allHints)
 
-	return result
+	// 7. Score the Dataset and its components, and fingerprint its problems.
+	scoreResult(result)
+	result.Fingerprint = Fingerprint(allHints)
+	result.Summary = generateSummary(result.IsHealthy, allHints, len(result.Silenced))
+
+	return result, nil
+}
+
+func sortHints(hints []types.FailureHint) {
+	sort.SliceStable(hints, func(i, j int) bool {
+		return hintLess(hints[i], hints[j])
+	})
+}
+
+func hintLess(hi, hj types.FailureHint) bool {
+	if hi.Severity != hj.Severity {
+		// e.g. "Critical" (8 chars) vs "Warning" (7 chars). Crude.
+		// Better: define numeric precedence.
+		return severityRank(hi.Severity) > severityRank(hj.Severity)
+	}
+	if hi.Component != hj.Component {
+		return hi.Component < hj.Component
+	}
+	if hi.ID != hj.ID {
+		return hi.ID < hj.ID
+	}
+	// Tie-breaker between findings of the same rule (e.g. one per pod).
+	if hi.Evidence.Name != hj.Evidence.Name {
+		return hi.Evidence.Name < hj.Evidence.Name
+	}
+	return hi.Evidence.Detail < hj.Evidence.Detail
+}
+
+// applySilences splits hints into active and silenced findings according to the
+// Dataset's silences. Expired silences are ignored; expiry is checked against the
+// snapshot time of the graph, falling back to now.
+func applySilences(g *types.ResourceGraph, hints []types.FailureHint, now time.Time) ([]types.FailureHint, []types.SilencedHint) {
+	if g.Dataset == nil || len(g.Dataset.Silences) == 0 {
+		return hints, nil
+	}
+	if !g.ObservedAt.IsZero() {
+		now = g.ObservedAt
+	}
+
+	active := make(map[string]types.Silence)
+	for _, s := range g.Dataset.Silences {
+		if s.ActiveAt(now) {
+			active[s.RuleID] = s
+		}
+	}
+
+	var kept []types.FailureHint
+	var silenced []types.SilencedHint
+	for _, h := range hints {
+		if s, ok := active[h.ID]; ok {
+			silenced = append(silenced, types.SilencedHint{FailureHint: h, Reason: s.Reason, Expires: s.Expires})
+			continue
+		}
+		kept = append(kept, h)
+	}
+	return kept, silenced
 }
 
 // severityRank helps sort FailureHints by importance.
@@ -71,19 +180,48 @@ func severityRank(s types.SeverityLevel) int {
 	}
 }
 
-func generateSummary(healthy bool, hints []types.FailureHint) string {
+func generateSummary(healthy bool, hints []types.FailureHint, silenced int) string {
+	if healthy && silenced > 0 {
+		return fmt.Sprintf("No active issues; %d silenced.", silenced)
+	}
 	if healthy {
 		return "Dataset is healthy and all components are ready."
 	}
 	// Simple summary: "Found X issues: Y critical, Z warnings."
 	crit := 0
 	warn := 0
+	info := 0
 	for _, h := range hints {
 		if h.Severity == types.SeverityCritical {
 			crit++
 		} else if h.Severity == types.SeverityWarning {
 			warn++
+		} else if h.Severity == types.SeverityInfo {
+			info++
+		}
+	}
+	summary := fmt.Sprintf("Found %d issues: %d critical, %d warnings.", len(hints), crit, warn)
+	if info > 0 {
+		summary = fmt.Sprintf("Found %d issues: %d critical, %d warnings, %d info.", len(hints), crit, warn, info)
+	}
+	if roots := rootCauseIDs(hints); len(roots) > 0 {
+		summary += fmt.Sprintf(" Root cause: %s.", strings.Join(roots, ", "))
+	}
+	if silenced > 0 {
+		summary += fmt.Sprintf(" %d silenced.", silenced)
+	}
+	return summary
+}
+
+// rootCauseIDs lists the distinct IDs of root-cause findings in result order.
+func rootCauseIDs(hints []types.FailureHint) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, h := range hints {
+		if h.RootCause && !seen[h.ID] {
+			seen[h.ID] = true
+			ids = append(ids, h.ID)
 		}
 	}
-	return fmt.Sprintf("Found %d issues: %d critical, %d warnings.", len(hints), crit, warn)
+	return ids
 }
@@ -1,11 +1,19 @@
 package diagnose_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/diagnose"
 	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 )
 
 func TestDiagnose_Healthy(t *testing.T) {
@@ -66,6 +74,70 @@ func TestDiagnose_WorkerPartialReady(t *testing.T) {
 	assert.Contains(t, hint.Evidence.Detail, "Ready replicas: 2/3")
 }
 
+func TestDiagnose_EvidenceObjects(t *testing.T) {
+	meta := func(name, uid string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "default", UID: k8stypes.UID(uid), ResourceVersion: "42"}
+	}
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Name: "demo-data", Namespace: "default", Status: "Bound"},
+		Runtime: &types.RuntimeInfo{
+			Name: "demo-data",
+			Type: "AlluxioRuntime",
+			Worker: &types.ComponentInfo{Name: "demo-data-worker", Ready: 2, Replicas: 3,
+				StatefulSet: &appsv1.StatefulSet{ObjectMeta: meta("demo-data-worker", "sts-uid")}},
+			Fuse: &types.ComponentInfo{Name: "demo-data-fuse", Ready: 1, Replicas: 1,
+				DaemonSet: &appsv1.DaemonSet{ObjectMeta: meta("demo-data-fuse", "ds-uid")}},
+		},
+	}
+
+	// A real workload kind, referenced down to the observed object.
+	hints := findings(diagnose.Diagnose(graph), "WORKER_PARTIALLY_READY")
+	require.Len(t, hints, 1)
+	ev := hints[0].Evidence
+	assert.Equal(t, "StatefulSet", ev.Kind)
+	assert.Equal(t, []types.ObjectRef{{APIVersion: "apps/v1", Kind: "StatefulSet", Namespace: "default", Name: "demo-data-worker", UID: "sts-uid", ResourceVersion: "42"}}, ev.Objects)
+	assert.Equal(t, []types.Fact{{Key: "readyReplicas", Value: "2"}, {Key: "replicas", Value: "3"}}, ev.Facts)
+	assert.Equal(t, "Ready replicas: 2/3", ev.Detail)
+
+	// Pods are referenced with their UID; engine findings name no object.
+	graph.Runtime.Worker.Pods = []types.PodInfo{{Name: "demo-data-worker-2", Status: "Pending",
+		Object: &corev1.Pod{ObjectMeta: meta("demo-data-worker-2", "pod-uid")}}}
+	hints = findings(diagnose.Diagnose(graph), "WORKER_PARTIALLY_READY")
+	require.Len(t, hints, 1)
+	assert.Equal(t, []types.ObjectRef{
+		{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "demo-data-worker-2", UID: "pod-uid", ResourceVersion: "42"},
+		{APIVersion: "apps/v1", Kind: "StatefulSet", Namespace: "default", Name: "demo-data-worker", UID: "sts-uid", ResourceVersion: "42"},
+	}, hints[0].Evidence.Objects)
+	// The detail is rendered from the facts.
+	assert.Equal(t, []types.Fact{{Key: "status", Value: "Pending"}, {Key: "restarts", Value: "0"}}, hints[0].Evidence.Facts)
+	assert.Equal(t, "Status: Pending, Restarts: 0", hints[0].Evidence.Detail)
+
+	set, err := diagnose.NewRuleSet(&panicRule{}, &factRule{})
+	require.NoError(t, err)
+	result := diagnose.DiagnoseWithRules(graph, set)
+	require.Len(t, result.FailureHints, 2)
+	for _, h := range result.FailureHints {
+		switch h.ID {
+		case "SITE_BUGGY":
+			assert.Empty(t, h.Evidence.Objects)
+		case "SITE_CACHE_RATIO":
+			// A rule reporting facts only gets its detail rendered from them.
+			assert.Equal(t, "cacheRatio=0.1, minimum=0.5", h.Evidence.Detail)
+			assert.Equal(t, "AlluxioRuntime", h.Evidence.Objects[0].Kind)
+			assert.Equal(t, "data.fluid.io/v1alpha1", h.Evidence.Objects[0].APIVersion)
+		}
+	}
+}
+
+type factRule struct{}
+
+func (r *factRule) ID() string { return "SITE_CACHE_RATIO" }
+
+func (r *factRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	return []types.FailureHint{{ID: r.ID(), Severity: types.SeverityInfo, Component: "Runtime",
+		Evidence: types.Evidence{Kind: g.Runtime.Type, Name: g.Runtime.Name, Facts: []types.Fact{{Key: "cacheRatio", Value: "0.1"}, {Key: "minimum", Value: "0.5"}}}}}
+}
+
 func TestDiagnose_ComplexFailure(t *testing.T) {
 	// Scenario: PVC Pending AND Worker Crash
 	graph := &types.ResourceGraph{
@@ -91,3 +163,938 @@ func TestDiagnose_ComplexFailure(t *testing.T) {
 	assert.Equal(t, types.SeverityWarning, result.FailureHints[1].Severity)
 	assert.Equal(t, "WORKER_PARTIALLY_READY", result.FailureHints[1].ID)
 }
+
+func TestDiagnose_HealthScore(t *testing.T) {
+	status := func(result *types.DiagnosticResult) map[string]types.HealthStatus {
+		out := make(map[string]types.HealthStatus)
+		for _, c := range result.Components {
+			out[c.Name] = c.Status
+		}
+		return out
+	}
+	healthy := func() *types.ResourceGraph {
+		return &types.ResourceGraph{
+			Dataset: &types.DatasetInfo{Status: "Bound"},
+			Runtime: &types.RuntimeInfo{
+				Master: &types.ComponentInfo{Ready: 1, Replicas: 1},
+				Worker: &types.ComponentInfo{Ready: 3, Replicas: 3},
+				Fuse:   &types.ComponentInfo{Ready: 3, Replicas: 3},
+			},
+		}
+	}
+
+	result := diagnose.Diagnose(healthy())
+	assert.Equal(t, 100, result.Score)
+	require.Len(t, result.Components, 6)
+	for _, c := range result.Components {
+		assert.Equal(t, types.HealthHealthy, c.Status, c.Name)
+	}
+
+	// A Warning caps the score at 89, whatever the weights.
+	graph := healthy()
+	graph.Runtime.Worker.Ready = 2
+	result = diagnose.Diagnose(graph)
+	assert.Equal(t, 89, result.Score)
+	assert.Equal(t, types.HealthDegraded, status(result)["worker"])
+	assert.Equal(t, types.HealthHealthy, status(result)["master"])
+
+	// A missing Runtime takes down master, worker and fuse.
+	result = diagnose.Diagnose(&types.ResourceGraph{Dataset: &types.DatasetInfo{Status: "Bound"}})
+	assert.Equal(t, types.HealthUnhealthy, status(result)["master"])
+	assert.Equal(t, types.HealthUnhealthy, status(result)["fuse"])
+	assert.Equal(t, types.HealthHealthy, status(result)["dataset"])
+	assert.Equal(t, 49, result.Score) // Weighted 64, capped by the Critical finding
+
+	// Silenced findings do not count.
+	graph = healthy()
+	graph.Runtime.Worker.Ready = 2
+	graph.Dataset.Silences = []types.Silence{{RuleID: "WORKER_PARTIALLY_READY"}}
+	assert.Equal(t, 100, diagnose.Diagnose(graph).Score)
+
+	// A Dataset still initializing is not degraded.
+	now := time.Date(2026, time.January, 1, 10, 0, 0, 0, time.UTC)
+	graph = healthy()
+	graph.ObservedAt = now
+	graph.Infrastructure = &types.InfrastructureInfo{PVC: &types.PVCInfo{Status: "Pending", CreationTimestamp: now.Add(-time.Minute)}}
+	result = diagnose.Diagnose(graph)
+	require.Equal(t, types.SeverityInfo, findings(result, "PVC_NOT_BOUND")[0].Severity)
+	assert.Equal(t, 100, result.Score)
+	assert.Equal(t, types.HealthHealthy, status(result)["storage"])
+
+	// Without a Dataset nothing was checked.
+	assert.Equal(t, 0, diagnose.Diagnose(&types.ResourceGraph{}).Score)
+
+	// A custom component's finding about a runtime pod counts against the pod's component.
+	graph = healthy()
+	graph.Runtime.Fuse.Pods = []types.PodInfo{{Name: "demo-data-fuse-abcde", Ready: true}}
+	result = diagnose.DiagnoseWithRules(graph, diagnose.RuleSet{&sitePodRule{pod: "demo-data-fuse-abcde"}})
+	assert.Equal(t, types.HealthDegraded, status(result)["fuse"])
+	assert.Equal(t, types.HealthHealthy, status(result)["operations"])
+	result = diagnose.DiagnoseWithRules(graph, diagnose.RuleSet{&sitePodRule{pod: "elsewhere"}})
+	assert.Equal(t, types.HealthHealthy, status(result)["fuse"])
+	assert.Equal(t, types.HealthDegraded, status(result)["operations"])
+}
+
+// sitePodRule reports a pod under a site-specific component.
+type sitePodRule struct{ pod string }
+
+func (r *sitePodRule) ID() string { return "SITE_POD_AUDIT" }
+
+func (r *sitePodRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	return []types.FailureHint{{ID: r.ID(), Severity: types.SeverityWarning, Component: "Audit",
+		Evidence: types.Evidence{Kind: "Pod", Name: r.pod}}}
+}
+
+func TestFingerprint(t *testing.T) {
+	crash := types.FailureHint{ID: "POD_CRASHLOOP_BACKOFF", Severity: types.SeverityWarning, Component: "Runtime/Worker",
+		Evidence: types.Evidence{Kind: "Pod", Name: "demo-data-worker-1", Detail: "Restarts: 5"}}
+	pvc := types.FailureHint{ID: "PVC_NOT_BOUND", Severity: types.SeverityCritical, Component: "Infrastructure/PVC",
+		Evidence: types.Evidence{Kind: "PersistentVolumeClaim", Name: "demo-data"}}
+	base := diagnose.Fingerprint([]types.FailureHint{crash, pvc})
+	assert.Len(t, base, 64)
+
+	// Order, details and severity do not change the set of problems.
+	later := crash
+	later.Severity = types.SeverityCritical
+	later.Evidence.Detail = "Restarts: 9"
+	assert.Equal(t, base, diagnose.Fingerprint([]types.FailureHint{pvc, later}))
+	assert.Equal(t, base, diagnose.Fingerprint([]types.FailureHint{pvc, crash, crash}))
+
+	// Another pod failing does.
+	other := crash
+	other.Evidence.Name = "demo-data-worker-2"
+	assert.NotEqual(t, base, diagnose.Fingerprint([]types.FailureHint{crash, pvc, other}))
+
+	// The same pod recreated is the same problem.
+	recreated := crash
+	recreated.Evidence.Objects = []types.ObjectRef{{APIVersion: "v1", Kind: "Pod", Name: "demo-data-worker-1", UID: "new-uid"}}
+	assert.Equal(t, base, diagnose.Fingerprint([]types.FailureHint{recreated, pvc}))
+
+	// Another field drifting on the same workload is another problem.
+	drift := types.FailureHint{ID: "RUNTIME_WORKLOAD_DRIFT", Component: "Runtime/Worker",
+		Evidence: types.Evidence{Kind: "StatefulSet", Name: "demo-data-worker", Facts: []types.Fact{{Key: "field", Value: "replicas"}, {Key: "desired", Value: "3"}}}}
+	otherField := drift
+	otherField.Evidence.Facts = []types.Fact{{Key: "field", Value: "image"}, {Key: "desired", Value: "3"}}
+	assert.NotEqual(t, diagnose.Fingerprint([]types.FailureHint{drift}), diagnose.Fingerprint([]types.FailureHint{otherField}))
+	// Its values are details.
+	otherValue := drift
+	otherValue.Evidence.Facts = []types.Fact{{Key: "field", Value: "replicas"}, {Key: "desired", Value: "4"}}
+	assert.Equal(t, diagnose.Fingerprint([]types.FailureHint{drift}), diagnose.Fingerprint([]types.FailureHint{otherValue}))
+
+	// So is another tiered store medium exceeding the same node.
+	mem := types.FailureHint{ID: "TIEREDSTORE_EXCEEDS_NODE_ALLOCATABLE", Component: "Runtime/Worker",
+		Evidence: types.Evidence{Kind: "Node", Name: "node-1", Facts: []types.Fact{{Key: "medium", Value: "MEM"}}}}
+	disk := mem
+	disk.Evidence.Facts = []types.Fact{{Key: "medium", Value: "SSD/HDD"}}
+	assert.NotEqual(t, diagnose.Fingerprint([]types.FailureHint{mem}), diagnose.Fingerprint([]types.FailureHint{disk}))
+
+	// And another container of the same pod failing.
+	oom := types.FailureHint{ID: "OOM_KILLED", Component: "Runtime/Worker",
+		Evidence: types.Evidence{Kind: "Pod", Name: "demo-data-worker-0", Facts: []types.Fact{{Key: "container", Value: "alluxio-worker"}, {Key: "restarts", Value: "3"}}}}
+	sidecar := oom
+	sidecar.Evidence.Facts = []types.Fact{{Key: "container", Value: "alluxio-job-worker"}, {Key: "restarts", Value: "3"}}
+	assert.NotEqual(t, diagnose.Fingerprint([]types.FailureHint{oom}), diagnose.Fingerprint([]types.FailureHint{sidecar}))
+
+	// Or a conflict on another host path or port with the same peer.
+	hostPath := types.FailureHint{ID: "HOST_PATH_CONFLICT", Component: "Runtime/Worker",
+		Evidence: types.Evidence{Kind: "Pod", Name: "demo-data-worker-0", Facts: []types.Fact{{Key: "path", Value: "/mnt/cache/mem"}}}}
+	otherPath := hostPath
+	otherPath.Evidence.Facts = []types.Fact{{Key: "path", Value: "/mnt/cache/ssd"}}
+	assert.NotEqual(t, diagnose.Fingerprint([]types.FailureHint{hostPath}), diagnose.Fingerprint([]types.FailureHint{otherPath}))
+
+	// And a conflict with another peer; the order of the objects is not.
+	conflict := func(objects ...types.ObjectRef) types.FailureHint {
+		return types.FailureHint{ID: "HOST_PORT_CONFLICT", Component: "Runtime/Worker",
+			Evidence: types.Evidence{Kind: "Pod", Name: "demo-data-worker-0", Objects: objects}}
+	}
+	self := types.ObjectRef{Kind: "Pod", Namespace: "default", Name: "demo-data-worker-0", UID: "a"}
+	peer := types.ObjectRef{Kind: "Pod", Namespace: "other", Name: "cache-worker-0", UID: "b"}
+	newPeer := types.ObjectRef{Kind: "Pod", Namespace: "other", Name: "cache-worker-1", UID: "c"}
+	assert.NotEqual(t, diagnose.Fingerprint([]types.FailureHint{conflict(self, peer)}), diagnose.Fingerprint([]types.FailureHint{conflict(self, newPeer)}))
+	assert.Equal(t, diagnose.Fingerprint([]types.FailureHint{conflict(self, peer, newPeer)}), diagnose.Fingerprint([]types.FailureHint{conflict(newPeer, self, peer)}))
+	recreatedPeer := peer
+	recreatedPeer.UID = "d"
+	assert.Equal(t, diagnose.Fingerprint([]types.FailureHint{conflict(self, peer)}), diagnose.Fingerprint([]types.FailureHint{conflict(self, recreatedPeer)}))
+
+	// Silenced findings are not active problems.
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Status: "Bound", Silences: []types.Silence{{RuleID: "WORKER_PARTIALLY_READY"}}},
+		Runtime: &types.RuntimeInfo{
+			Master: &types.ComponentInfo{Ready: 1, Replicas: 1},
+			Worker: &types.ComponentInfo{Name: "demo-data-worker", Ready: 2, Replicas: 3},
+		},
+	}
+	assert.Equal(t, diagnose.Fingerprint(nil), diagnose.Diagnose(graph).Fingerprint)
+}
+
+func TestDiagnose_PVCPendingEscalatesWithAge(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 10, 0, 0, 0, time.UTC)
+	cases := []struct {
+		age      time.Duration
+		severity types.SeverityLevel
+	}{
+		{age: 30 * time.Second, severity: types.SeverityInfo},     // Within grace period
+		{age: 3 * time.Minute, severity: types.SeverityWarning},   // Past grace, before CriticalAfter
+		{age: 10 * time.Minute, severity: types.SeverityCritical}, // Fully escalated
+	}
+
+	for _, tc := range cases {
+		graph := &types.ResourceGraph{
+			ObservedAt: now,
+			Dataset:    &types.DatasetInfo{Status: "Bound"},
+			Runtime:    &types.RuntimeInfo{},
+			Infrastructure: &types.InfrastructureInfo{
+				PVC: &types.PVCInfo{Name: "demo-data", Status: "Pending", CreationTimestamp: now.Add(-tc.age)},
+			},
+		}
+
+		result := diagnose.Diagnose(graph)
+
+		assert.Len(t, result.FailureHints, 1)
+		assert.Equal(t, "PVC_NOT_BOUND", result.FailureHints[0].ID)
+		assert.Equal(t, tc.severity, result.FailureHints[0].Severity, "age %s", tc.age)
+		assert.NotEmpty(t, result.FailureHints[0].Context)
+	}
+}
+
+func TestDiagnose_WorkerPerPodFindings(t *testing.T) {
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Status: "Bound"},
+		Runtime: &types.RuntimeInfo{
+			Worker: &types.ComponentInfo{
+				Name: "demo-data-worker", Ready: 1, Replicas: 3,
+				Pods: []types.PodInfo{
+					{Name: "demo-data-worker-2", Status: "ImagePullBackOff", Node: "node-3"},
+					{Name: "demo-data-worker-0", Status: "Running", Ready: true, Node: "node-1"},
+					{Name: "demo-data-worker-1", Status: "OOMKilled", Restarts: 4, Node: "node-2"},
+				},
+			},
+		},
+	}
+
+	result := diagnose.Diagnose(graph)
+
+	// One finding per unready pod, sorted by pod name.
+	assert.Len(t, result.FailureHints, 2)
+	assert.Equal(t, "WORKER_PARTIALLY_READY", result.FailureHints[0].ID)
+	assert.Equal(t, "Pod", result.FailureHints[0].Evidence.Kind)
+	assert.Equal(t, "demo-data-worker-1", result.FailureHints[0].Evidence.Name)
+	assert.Contains(t, result.FailureHints[0].Evidence.Detail, "OOMKilled")
+	assert.Equal(t, "demo-data-worker-2", result.FailureHints[1].Evidence.Name)
+	assert.Contains(t, result.FailureHints[1].Evidence.Detail, "ImagePullBackOff")
+}
+
+func TestDiagnose_OOMKilled(t *testing.T) {
+	oom := &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Status: "Bound"},
+		Runtime: &types.RuntimeInfo{
+			Type: "AlluxioRuntime",
+			Worker: &types.ComponentInfo{
+				Name: "demo-data-worker", Ready: 1, Replicas: 2,
+				Pods: []types.PodInfo{
+					{Name: "demo-data-worker-0", Ready: true, Containers: []types.ContainerInfo{
+						{Name: "worker", Ready: true, RestartCount: 2, MemoryLimit: "4Gi", LastState: oom},
+					}},
+					{Name: "demo-data-worker-1", Containers: []types.ContainerInfo{
+						{Name: "worker", RestartCount: 5, MemoryLimit: "4Gi", State: oom},
+					}},
+				},
+			},
+			Fuse: &types.ComponentInfo{
+				Name: "demo-data-fuse", Ready: 1, Replicas: 1,
+				Pods: []types.PodInfo{
+					{Name: "demo-data-fuse-abcde", Ready: true, Containers: []types.ContainerInfo{
+						{Name: "fuse", Ready: true, LastState: &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error"}}},
+					}},
+				},
+			},
+		},
+	}
+
+	result := diagnose.Diagnose(graph)
+
+	var ooms []types.FailureHint
+	for _, h := range result.FailureHints {
+		if h.ID == "OOM_KILLED" {
+			ooms = append(ooms, h)
+		}
+	}
+	assert.Len(t, ooms, 2)
+
+	// Currently OOMKilled: Critical. Recovered: Warning.
+	assert.Equal(t, types.SeverityCritical, ooms[0].Severity)
+	assert.Equal(t, "demo-data-worker-1", ooms[0].Evidence.Name)
+	assert.Contains(t, ooms[0].Evidence.Detail, "Memory limit: 4Gi")
+	assert.Contains(t, ooms[0].Evidence.Detail, "Restarts: 5")
+	assert.Contains(t, ooms[0].Suggestion, "spec.worker.resources")
+	assert.Equal(t, types.SeverityWarning, ooms[1].Severity)
+	assert.Equal(t, "demo-data-worker-0", ooms[1].Evidence.Name)
+}
+
+func TestDiagnose_Remediation(t *testing.T) {
+	oom := &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Name: "demo-data", Namespace: "ml", Status: "Bound"},
+		Runtime: &types.RuntimeInfo{
+			Name: "demo-data",
+			Type: "AlluxioRuntime",
+			Worker: &types.ComponentInfo{
+				Name: "demo-data-worker", Ready: 0, Replicas: 1,
+				Pods: []types.PodInfo{{Name: "demo-data-worker-0", Restarts: 3, Containers: []types.ContainerInfo{
+					{Name: "worker", RestartCount: 3, MemoryLimit: "4Gi", State: oom},
+				}}},
+			},
+		},
+		Infrastructure: &types.InfrastructureInfo{PVC: &types.PVCInfo{Name: "demo-data", Status: "Pending"}},
+	}
+
+	result := diagnose.Diagnose(graph)
+	byID := make(map[string]types.FailureHint)
+	for _, h := range result.FailureHints {
+		byID[h.ID] = h
+		for _, step := range h.Remediation {
+			assert.NotEmpty(t, step.Description, h.ID)
+			if step.Command != "" {
+				verb := strings.Fields(step.Command)[1]
+				assert.Contains(t, []string{"get", "describe", "logs"}, verb, "%s: commands must only read", h.ID)
+			}
+		}
+	}
+
+	oomPlan := byID["OOM_KILLED"].Remediation
+	require.Len(t, oomPlan, 3)
+	assert.Equal(t, "kubectl describe pod demo-data-worker-0 -n ml", oomPlan[0].Command)
+	assert.Equal(t, "kubectl logs demo-data-worker-0 -c worker --previous -n ml", oomPlan[1].Command)
+	require.NotNil(t, oomPlan[2].Patch)
+	assert.Equal(t, types.Patch{Kind: "AlluxioRuntime", Name: "demo-data", Namespace: "ml",
+		YAML: "spec:\n  worker:\n    resources:\n      limits:\n        memory: 8Gi\n"}, *oomPlan[2].Patch)
+
+	// Rules without their own plan get the inspection steps of their evidence.
+	require.Len(t, byID["PVC_NOT_BOUND"].Remediation, 1)
+	assert.Equal(t, "kubectl describe pvc demo-data -n ml", byID["PVC_NOT_BOUND"].Remediation[0].Command)
+	assert.Equal(t, "kubectl describe pod demo-data-worker-0 -n ml", byID["WORKER_PARTIALLY_READY"].Remediation[0].Command)
+}
+
+func TestDiagnose_PodFailures(t *testing.T) {
+	waiting := func(reason, msg string) *corev1.ContainerState {
+		return &corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: msg}}
+	}
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Status: "Bound", Namespace: "team-a"},
+		Runtime: &types.RuntimeInfo{
+			Type: "AlluxioRuntime",
+			Master: &types.ComponentInfo{
+				Name: "demo-data-master", Ready: 0, Replicas: 1,
+				Pods: []types.PodInfo{{Name: "demo-data-master-0", Phase: "Pending", Unschedulable: "0/3 nodes are available: 3 Insufficient memory."}},
+			},
+			Worker: &types.ComponentInfo{
+				Name: "demo-data-worker", Ready: 0, Replicas: 5,
+				Pods: []types.PodInfo{
+					{Name: "demo-data-worker-0", Containers: []types.ContainerInfo{{Name: "worker", RestartCount: 3, State: waiting("CrashLoopBackOff", "")}}},
+					{Name: "demo-data-worker-1", Containers: []types.ContainerInfo{{Name: "worker", Image: "alluxio:bad", State: waiting("ErrImagePull", "")}}},
+					{Name: "demo-data-worker-2", Containers: []types.ContainerInfo{{Name: "worker", State: waiting("CreateContainerConfigError", `secret "s3-creds" not found`)}}},
+					{Name: "demo-data-worker-3", Containers: []types.ContainerInfo{
+						{Name: "init-users", Init: true, State: &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}}},
+						{Name: "worker", State: waiting("PodInitializing", "")},
+					}},
+					{Name: "demo-data-worker-4", Phase: "Failed", Reason: "Evicted", Message: "The node was low on resource: ephemeral-storage.", Node: "node-4"},
+				},
+			},
+		},
+	}
+
+	result := diagnose.Diagnose(graph)
+
+	byID := make(map[string]types.FailureHint)
+	for _, h := range result.FailureHints {
+		if h.Evidence.Kind == "Pod" {
+			byID[h.ID] = h
+		}
+	}
+	// Each pod's failure explains its readiness finding, which keeps it off the root causes.
+	for _, h := range result.FailureHints {
+		if h.ID == "MASTER_NOT_READY" || h.ID == "WORKER_PARTIALLY_READY" {
+			assert.False(t, h.RootCause, "%s %s", h.ID, h.Evidence.Name)
+			delete(byID, h.ID)
+		}
+	}
+	assert.Len(t, byID, 6)
+
+	assert.Equal(t, "demo-data-master-0", byID["POD_UNSCHEDULABLE"].Evidence.Name)
+	assert.Equal(t, types.SeverityCritical, byID["POD_UNSCHEDULABLE"].Severity)
+	assert.Contains(t, byID["POD_UNSCHEDULABLE"].Evidence.Detail, "Insufficient memory")
+
+	assert.Equal(t, "demo-data-worker-0", byID["POD_CRASHLOOP_BACKOFF"].Evidence.Name)
+	assert.Equal(t, types.SeverityWarning, byID["POD_CRASHLOOP_BACKOFF"].Severity)
+	assert.Contains(t, byID["POD_CRASHLOOP_BACKOFF"].Suggestion, "--previous -n team-a")
+
+	assert.Contains(t, byID["POD_IMAGE_PULL_FAILED"].Evidence.Detail, "alluxio:bad")
+	assert.Contains(t, byID["POD_CONFIG_ERROR"].Evidence.Detail, `secret "s3-creds" not found`)
+	assert.Contains(t, byID["POD_INIT_FAILED"].Evidence.Detail, "init-users")
+	assert.Contains(t, byID["POD_EVICTED"].Evidence.Detail, "ephemeral-storage")
+}
+
+func TestDiagnose_SchedulingAnalysis(t *testing.T) {
+	taint := corev1.Taint{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Status: "Bound"},
+		Runtime: &types.RuntimeInfo{
+			Type: "JuiceFSRuntime",
+			Fuse: &types.ComponentInfo{
+				Name: "demo-data-fuse", Ready: 1, Replicas: 2,
+				Pods: []types.PodInfo{
+					{Name: "demo-data-fuse-a", Ready: true, Node: "node-1"},
+					{Name: "demo-data-fuse-b", Phase: "Pending", NodeSelector: map[string]string{"pool": "cache"}},
+				},
+			},
+		},
+		Nodes: []types.NodeInfo{
+			{Name: "node-1", Ready: true, Labels: map[string]string{"pool": "cache"}},
+			{Name: "node-2", Ready: true, Taints: []corev1.Taint{taint}},
+			{Name: "node-3", Ready: true, Taints: []corev1.Taint{taint}},
+			{Name: "node-4", Ready: false},
+		},
+		Events: []types.EventInfo{
+			{Kind: "Pod", Name: "demo-data-fuse-b", Reason: "FailedScheduling", Message: "0/4 nodes are available: 1 node(s) didn't match Pod's node affinity/selector."},
+			// The latest event wins.
+			{Kind: "Pod", Name: "demo-data-fuse-b", Reason: "FailedScheduling", Message: "0/4 nodes are available: 1 node(s) didn't have free ports for the requested pod ports, 2 node(s) had untolerated taint {dedicated: gpu}, 1 node(s) had untolerated taint {node.kubernetes.io/not-ready: }. preemption: not eligible."},
+		},
+	}
+
+	result := diagnose.Diagnose(graph)
+
+	var hint *types.FailureHint
+	for i, h := range result.FailureHints {
+		if h.ID == "POD_UNSCHEDULABLE" {
+			hint = &result.FailureHints[i]
+		}
+	}
+	if assert.NotNil(t, hint) {
+		assert.Equal(t, "demo-data-fuse-b", hint.Evidence.Name)
+		assert.Contains(t, hint.Evidence.Detail, "most excluded by: node(s) had untolerated taint {dedicated: gpu} (2)")
+		assert.Contains(t, hint.Evidence.Detail, "Taints: dedicated=gpu:NoSchedule on 2 node(s)")
+		assert.Contains(t, hint.Context, "Nodes: 4 (3 ready, 0 cordoned)")
+		assert.Contains(t, hint.Suggestion, "tolerations")
+	}
+}
+
+func TestDiagnose_TieredStoreCapacity(t *testing.T) {
+	worker := func(name, node string) types.PodInfo {
+		return types.PodInfo{Name: name, Ready: true, Node: node, Containers: []types.ContainerInfo{
+			{Name: "worker", Ready: true, MemoryLimit: "4Gi"},
+			{Name: "init", Init: true}, // Init containers do not count towards the limit
+		}}
+	}
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Status: "Bound"},
+		Runtime: &types.RuntimeInfo{
+			Name: "demo-data",
+			Type: "AlluxioRuntime",
+			TieredStore: []types.TieredStoreLevel{
+				{MediumType: "MEM", Paths: []string{"/dev/shm"}, Quota: resource.MustParse("6Gi")},
+				{MediumType: "HDD", Paths: []string{"/mnt/hdd"}, Quota: resource.MustParse("50Gi")},
+			},
+			Worker: &types.ComponentInfo{
+				Name: "demo-data-worker", Ready: 2, Replicas: 2,
+				Pods: []types.PodInfo{worker("demo-data-worker-0", "node-1"), worker("demo-data-worker-1", "node-2")},
+			},
+		},
+		Nodes: []types.NodeInfo{
+			{Name: "node-1", Ready: true, Allocatable: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("5Gi")}},
+			{Name: "node-2", Ready: true, Pressures: []string{"DiskPressure"}, Allocatable: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Gi")}},
+			{Name: "node-3", Ready: true, Pressures: []string{"DiskPressure"}}, // No worker here
+		},
+	}
+
+	result := diagnose.Diagnose(graph)
+
+	found := make(map[string]types.FailureHint)
+	for _, h := range result.FailureHints {
+		found[h.ID] = h
+	}
+	assert.Len(t, result.FailureHints, 3)
+	assert.Equal(t, "MEM quota: 6Gi, Worker memory limit: 4Gi (pod demo-data-worker-0)", found["TIEREDSTORE_MEM_EXCEEDS_LIMIT"].Evidence.Detail)
+	assert.Equal(t, "node-1", found["TIEREDSTORE_EXCEEDS_NODE_ALLOCATABLE"].Evidence.Name)
+	assert.Equal(t, "node-2", found["TIEREDSTORE_DISK_PRESSURE"].Evidence.Name)
+	assert.Contains(t, found["TIEREDSTORE_DISK_PRESSURE"].Evidence.Detail, "/mnt/hdd")
+}
+
+func TestDiagnose_RuntimeWorkloadDrift(t *testing.T) {
+	replicas := func(n int32) *int32 { return &n }
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Status: "Bound"},
+		Runtime: &types.RuntimeInfo{
+			Type: "JindoRuntime",
+			Worker: &types.ComponentInfo{
+				Name: "demo-data-worker", Ready: 3, Replicas: 3,
+				Desired: &types.ComponentSpec{Replicas: replicas(5), ImageTag: "6.2.1", Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+					corev1.ResourceCPU:    resource.MustParse("2"),
+				}},
+				Actual: &types.ComponentSpec{Replicas: replicas(3), Image: "jindofs", ImageTag: "6.2.0", Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("8192Mi"), // Same quantity, different notation
+				}},
+			},
+			Fuse: &types.ComponentInfo{
+				Name: "demo-data-fuse", Ready: 1, Replicas: 1,
+				// Nothing declared: nothing to drift from.
+				Desired: &types.ComponentSpec{},
+				Actual:  &types.ComponentSpec{Image: "jindofs-fuse", ImageTag: "6.2.0"},
+			},
+		},
+	}
+
+	result := diagnose.Diagnose(graph)
+
+	var details []string
+	for _, h := range result.FailureHints {
+		assert.Equal(t, "RUNTIME_WORKLOAD_DRIFT", h.ID)
+		assert.Equal(t, "StatefulSet", h.Evidence.Kind)
+		details = append(details, h.Evidence.Detail)
+	}
+	assert.Equal(t, []string{
+		"imageTag: JindoRuntime spec 6.2.1, StatefulSet 6.2.0",
+		"replicas: JindoRuntime spec 5, StatefulSet 3",
+		"requests.cpu: JindoRuntime spec 2, StatefulSet <unset>",
+	}, details)
+}
+
+func TestDiagnose_ImageVersionSkew(t *testing.T) {
+	pod := func(name, image string) types.PodInfo {
+		return types.PodInfo{Name: name, Ready: true, Containers: []types.ContainerInfo{
+			{Name: "init", Image: "busybox:1.36", Init: true},
+			{Name: "main", Image: image},
+		}}
+	}
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Status: "Bound"},
+		Runtime: &types.RuntimeInfo{
+			Name: "demo-data",
+			Type: "JuiceFSRuntime",
+			// Pods unknown: the tag comes from the StatefulSet.
+			Master: &types.ComponentInfo{Name: "demo-data-master", Ready: 1, Replicas: 1, Actual: &types.ComponentSpec{ImageTag: "ce-v1.1.0"}},
+			Worker: &types.ComponentInfo{Name: "demo-data-worker", Ready: 1, Replicas: 1,
+				Pods: []types.PodInfo{pod("demo-data-worker-0", "juicedata/juicefs-fuse:ce-v1.1.0")}},
+			Fuse: &types.ComponentInfo{Name: "demo-data-fuse", Ready: 3, Replicas: 3,
+				Pods: []types.PodInfo{
+					pod("demo-data-fuse-a", "juicedata/juicefs-fuse:ce-v1.0.4"),
+					pod("demo-data-fuse-b", "juicedata/juicefs-fuse:ce-v1.1.0"),
+					pod("demo-data-fuse-c", "juicedata/juicefs-fuse:ce-v1.0.4"),
+				}},
+		},
+		Controllers: []types.ControllerInfo{
+			{Name: "csi-nodeplugin-fluid", Images: []string{"registry.k8s.io/sig-storage/csi-node-driver-registrar:v2.9.0", "fluidcloudnative/fluid-csi:v1.0.0"}},
+			{Name: "dataset-controller", Images: []string{"fluidcloudnative/dataset-controller:v1.0.0"}},
+		},
+	}
+
+	result := diagnose.Diagnose(graph)
+
+	details := make(map[string]string)
+	for _, h := range result.FailureHints {
+		assert.Equal(t, "IMAGE_VERSION_SKEW", h.ID)
+		details[h.Component] = h.Evidence.Detail
+	}
+	assert.Equal(t, map[string]string{
+		"Runtime/Fuse": "Pods run different images: juicedata/juicefs-fuse:ce-v1.0.4 (2 pods), juicedata/juicefs-fuse:ce-v1.1.0 (1 pod)",
+		"Runtime":      "Image tags differ: master ce-v1.1.0, worker ce-v1.1.0, fuse ce-v1.0.4",
+	}, details, "the CSI sidecar's tag is not compared with the controllers")
+}
+
+func TestDiagnose_ScaledDownComponents(t *testing.T) {
+	zero := int32(0)
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Status: "Bound"},
+		Runtime: &types.RuntimeInfo{
+			Type: "AlluxioRuntime",
+			// Scaling down: the StatefulSet wants none, one pod is still terminating.
+			Master: &types.ComponentInfo{Name: "demo-data-master", Replicas: 0, Ready: 1, State: "ComponentsScaledDown",
+				Pods: []types.PodInfo{{Name: "demo-data-master-0", Status: "Terminating", Restarts: 4, Containers: []types.ContainerInfo{
+					{Name: "alluxio-master", State: &corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+				}}}},
+			Worker: &types.ComponentInfo{Name: "demo-data-worker", Replicas: 0, Ready: 0, State: "ComponentsScaledDown",
+				Desired: &types.ComponentSpec{Replicas: &zero}},
+		},
+	}
+
+	result := diagnose.Diagnose(graph)
+	assert.True(t, result.IsHealthy, "intentionally scaled-down components are not failures: %v", result.FailureHints)
+
+	// Scaled to zero against the Runtime spec is drift, not an intentional scale-down.
+	five := int32(5)
+	graph.Runtime.Worker.Desired = &types.ComponentSpec{Replicas: &five}
+	graph.Runtime.Worker.Actual = &types.ComponentSpec{Replicas: &zero}
+	result = diagnose.Diagnose(graph)
+	require.Len(t, result.FailureHints, 1)
+	assert.Equal(t, "RUNTIME_WORKLOAD_DRIFT", result.FailureHints[0].ID)
+}
+
+func TestDiagnose_OnDemandFuse(t *testing.T) {
+	label := "fluid.io/f-default-demo-data"
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Name: "demo-data", Namespace: "default", Status: "Bound"},
+		Runtime: &types.RuntimeInfo{
+			Type:   "AlluxioRuntime",
+			Master: &types.ComponentInfo{Name: "demo-data-master", Replicas: 1, Ready: 1},
+			Fuse: &types.ComponentInfo{Name: "demo-data-fuse", Replicas: 2, Ready: 1,
+				NodeSelector: map[string]string{label: "true"},
+				Pods: []types.PodInfo{
+					{Name: "demo-data-fuse-a", Status: "Running", Ready: true, Node: "node-1"},
+					// Unready, but no pod mounting the Dataset runs on node-3.
+					{Name: "demo-data-fuse-c", Status: "CrashLoopBackOff", Node: "node-3"},
+				}},
+		},
+		Nodes: []types.NodeInfo{
+			{Name: "node-1", Ready: true, Labels: map[string]string{label: "true"}},
+			{Name: "node-2", Ready: true},
+			{Name: "node-3", Ready: true, Labels: map[string]string{label: "true"}},
+		},
+		Consumers: []types.PodInfo{
+			{Name: "train-0", Phase: "Running", Node: "node-1"},
+			{Name: "train-1", Phase: "Pending", Node: "node-2"},
+			{Name: "train-2", Phase: "Pending"}, // Not placed yet
+		},
+	}
+
+	result := diagnose.Diagnose(graph)
+	require.Len(t, result.FailureHints, 1)
+	h := result.FailureHints[0]
+	assert.Equal(t, "FUSE_MISSING", h.ID)
+	assert.Equal(t, "node-2", h.Evidence.Name)
+	assert.Equal(t, "No fuse pod on node node-2; node lacks on-demand fuse label fluid.io/f-default-demo-data; pods mounting the Dataset: train-1", h.Evidence.Detail)
+
+	// Nothing mounts the Dataset: an on-demand fuse with no replicas is expected.
+	graph.Consumers = []types.PodInfo{}
+	graph.Runtime.Fuse = &types.ComponentInfo{Name: "demo-data-fuse", NodeSelector: map[string]string{label: "true"}}
+	assert.True(t, diagnose.Diagnose(graph).IsHealthy)
+}
+
+func TestDiagnose_RootCauseCorrelation(t *testing.T) {
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Name: "demo-data", Status: "NotBound"},
+		Runtime: nil,
+		Infrastructure: &types.InfrastructureInfo{
+			PVC: &types.PVCInfo{Name: "demo-data", Status: "Pending"},
+		},
+	}
+
+	result := diagnose.Diagnose(graph)
+
+	hints := make(map[string]types.FailureHint)
+	for _, h := range result.FailureHints {
+		hints[h.ID] = h
+	}
+	assert.Len(t, hints, 3)
+	assert.True(t, hints["RUNTIME_MISSING"].RootCause)
+	assert.Empty(t, hints["RUNTIME_MISSING"].CausedBy)
+	assert.False(t, hints["DATASET_NOT_BOUND"].RootCause)
+	assert.Equal(t, []string{"RUNTIME_MISSING"}, hints["DATASET_NOT_BOUND"].CausedBy)
+	assert.Equal(t, []string{"RUNTIME_MISSING", "DATASET_NOT_BOUND"}, hints["PVC_NOT_BOUND"].CausedBy)
+	assert.Contains(t, result.Summary, "Root cause: RUNTIME_MISSING.")
+}
+
+// cyclicRule declares a cause that in turn declares it as its cause.
+type cyclicRule struct {
+	id, cause string
+	severity  types.SeverityLevel
+}
+
+func (r *cyclicRule) ID() string { return r.id }
+
+func (r *cyclicRule) CausedBy() []string { return []string{r.cause} }
+
+func (r *cyclicRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	return []types.FailureHint{{ID: r.id, Severity: r.severity, Component: "Dataset"}}
+}
+
+func TestDiagnose_CausalCycle(t *testing.T) {
+	graph := &types.ResourceGraph{Dataset: &types.DatasetInfo{Name: "demo-data", Status: "Bound"}}
+	result := diagnose.DiagnoseWithRules(graph, diagnose.RuleSet{
+		&cyclicRule{id: "SITE_QUOTA_EXCEEDED", cause: "SITE_CACHE_EVICTING", severity: types.SeverityCritical},
+		&cyclicRule{id: "SITE_CACHE_EVICTING", cause: "SITE_QUOTA_EXCEEDED", severity: types.SeverityWarning},
+		&labelRule{},
+	})
+
+	hints := make(map[string]types.FailureHint)
+	for _, h := range result.FailureHints {
+		hints[h.ID] = h
+	}
+	require.Len(t, hints, 3)
+	assert.True(t, hints["SITE_TEAM_LABEL_MISSING"].RootCause)
+	// The unrelated root leaves the cycle unexplained: its most severe finding is promoted.
+	assert.True(t, hints["SITE_QUOTA_EXCEEDED"].RootCause)
+	assert.False(t, hints["SITE_CACHE_EVICTING"].RootCause)
+	assert.Equal(t, []string{"SITE_QUOTA_EXCEEDED"}, hints["SITE_CACHE_EVICTING"].CausedBy)
+}
+
+// labelRule is a site-specific rule as a library consumer would write it.
+type labelRule struct{}
+
+func (r *labelRule) ID() string { return "SITE_TEAM_LABEL_MISSING" }
+
+func (r *labelRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	if g.Dataset.Labels["team"] == "" {
+		return []types.FailureHint{{ID: r.ID(), Severity: types.SeverityInfo, Component: "Dataset"}}
+	}
+	return nil
+}
+
+func TestRegistry_CustomRules(t *testing.T) {
+	registry := diagnose.NewRegistry()
+	for _, r := range diagnose.Rules() {
+		assert.NoError(t, registry.Register(r))
+	}
+	assert.NoError(t, registry.Register(&labelRule{}))
+	assert.Error(t, registry.Register(&labelRule{}), "duplicate IDs must be rejected")
+
+	rule, ok := registry.Lookup("SITE_TEAM_LABEL_MISSING")
+	assert.True(t, ok)
+	assert.Equal(t, "SITE_TEAM_LABEL_MISSING", rule.ID())
+
+	// Rule sets keep registration order regardless of the requested order.
+	set, err := registry.RuleSet("SITE_TEAM_LABEL_MISSING", "RUNTIME_MISSING")
+	assert.NoError(t, err)
+	assert.Equal(t, "RUNTIME_MISSING", set[0].ID())
+	assert.Equal(t, "SITE_TEAM_LABEL_MISSING", set[1].ID())
+
+	_, err = registry.RuleSet("NO_SUCH_RULE")
+	assert.Error(t, err)
+
+	result := diagnose.DiagnoseWithRules(&types.ResourceGraph{Dataset: &types.DatasetInfo{Status: "Bound"}}, set)
+	assert.Len(t, result.FailureHints, 2)
+	assert.Equal(t, "RUNTIME_MISSING", result.FailureHints[0].ID)
+	assert.Equal(t, "SITE_TEAM_LABEL_MISSING", result.FailureHints[1].ID)
+}
+
+// panicRule stands for a rule with a bug.
+type panicRule struct{}
+
+func (r *panicRule) ID() string { return "SITE_BUGGY" }
+
+func (r *panicRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	return []types.FailureHint{{ID: r.ID(), Evidence: types.Evidence{Name: g.Runtime.Master.Name}}}
+}
+
+func TestDiagnose_RulePanicIsolated(t *testing.T) {
+	set, err := diagnose.DefaultRegistry.RuleSet()
+	require.NoError(t, err)
+	set, err = set.With(&panicRule{})
+	require.NoError(t, err)
+
+	// No Runtime: the buggy rule dereferences nil, the built-ins still report.
+	result := diagnose.DiagnoseWithRules(&types.ResourceGraph{Dataset: &types.DatasetInfo{Name: "demo-data", Status: "Bound"}}, set)
+
+	ids := make(map[string]types.FailureHint)
+	for _, h := range result.FailureHints {
+		ids[h.ID] = h
+	}
+	assert.Contains(t, ids, "RUNTIME_MISSING")
+	require.Contains(t, ids, diagnose.RuleErrorID)
+	assert.Equal(t, "SITE_BUGGY", ids[diagnose.RuleErrorID].Evidence.Name)
+	assert.Contains(t, ids[diagnose.RuleErrorID].Evidence.Detail, "nil pointer dereference")
+}
+
+func TestDiagnose_InvalidGraph(t *testing.T) {
+	result := diagnose.Diagnose(&types.ResourceGraph{Runtime: &types.RuntimeInfo{Type: "AlluxioRuntime"}})
+	assert.False(t, result.IsHealthy)
+	require.Len(t, result.FailureHints, 1)
+	assert.Equal(t, diagnose.InvalidGraphID, result.FailureHints[0].ID)
+	assert.True(t, result.FailureHints[0].RootCause)
+}
+
+func TestDiagnoseWithProfile(t *testing.T) {
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Status: "Bound"},
+		Runtime: &types.RuntimeInfo{
+			Worker: &types.ComponentInfo{Name: "demo-data-worker", Ready: 1, Replicas: 3},
+			Fuse:   &types.ComponentInfo{Name: "demo-data-fuse", Ready: 0, Replicas: 2},
+		},
+		Infrastructure: &types.InfrastructureInfo{
+			PVC: &types.PVCInfo{Status: "Pending"},
+		},
+	}
+	profile := &diagnose.Profile{
+		Name: "dev",
+		Rules: map[string]diagnose.RuleOverride{
+			"PVC_NOT_BOUND":          {Disabled: true},
+			"FUSE_MISSING":           {Severity: types.SeverityInfo},
+			"WORKER_PARTIALLY_READY": {Params: map[string]interface{}{"criticalReadyRatio": 0.5}},
+		},
+	}
+	builtins, _ := diagnose.DefaultRegistry.RuleSet()
+
+	result, err := diagnose.DiagnoseWithProfile(graph, builtins, profile)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "dev", result.Profile)
+	assert.Len(t, result.FailureHints, 2)
+	assert.Equal(t, "WORKER_PARTIALLY_READY", result.FailureHints[0].ID)
+	assert.Equal(t, types.SeverityCritical, result.FailureHints[0].Severity) // 1/3 ready is below 50%
+	assert.Equal(t, "FUSE_MISSING", result.FailureHints[1].ID)
+	assert.Equal(t, types.SeverityInfo, result.FailureHints[1].Severity)
+
+	// The shared built-in rules are not modified by the profile.
+	assert.Equal(t, types.SeverityWarning, diagnose.Diagnose(graph).FailureHints[1].Severity)
+
+	// The profile's severity replaces the base severity: findings still in their grace
+	// period stay Info, and Critical ones stay Warning until the critical threshold.
+	critical := &diagnose.Profile{Rules: map[string]diagnose.RuleOverride{"PVC_NOT_BOUND": {Severity: types.SeverityCritical}}}
+	now := time.Date(2026, time.January, 1, 10, 0, 0, 0, time.UTC)
+	young := &types.ResourceGraph{
+		Dataset:        &types.DatasetInfo{Status: "Bound"},
+		Infrastructure: &types.InfrastructureInfo{PVC: &types.PVCInfo{Status: "Pending", CreationTimestamp: now.Add(-time.Minute)}},
+		ObservedAt:     now,
+	}
+	result, err = diagnose.DiagnoseWithProfile(young, builtins, critical)
+	require.NoError(t, err)
+	pvc := findings(result, "PVC_NOT_BOUND")
+	require.Len(t, pvc, 1)
+	assert.Equal(t, types.SeverityInfo, pvc[0].Severity)
+	young.Infrastructure.PVC.CreationTimestamp = now.Add(-3 * time.Minute)
+	result, err = diagnose.DiagnoseWithProfile(young, builtins, critical)
+	require.NoError(t, err)
+	assert.Equal(t, types.SeverityWarning, findings(result, "PVC_NOT_BOUND")[0].Severity)
+	young.Infrastructure.PVC.CreationTimestamp = now.Add(-time.Hour)
+	result, err = diagnose.DiagnoseWithProfile(young, builtins, critical)
+	require.NoError(t, err)
+	assert.Equal(t, types.SeverityCritical, findings(result, "PVC_NOT_BOUND")[0].Severity)
+
+	// Rules that do not escalate report the profile's severity whatever their context says.
+	warn := &diagnose.Profile{Rules: map[string]diagnose.RuleOverride{"SITE_PROVISIONING": {Severity: types.SeverityWarning}}}
+	result, err = diagnose.DiagnoseWithProfile(young, diagnose.RuleSet{&provisioningRule{}}, warn)
+	require.NoError(t, err)
+	assert.Equal(t, types.SeverityWarning, findings(result, "SITE_PROVISIONING")[0].Severity)
+
+	for name, bad := range map[string]diagnose.RuleOverride{
+		"UNKNOWN_RULE":           {Disabled: true},
+		"WORKER_PARTIALLY_READY": {Params: map[string]interface{}{"noSuchParam": 1}},
+		"FUSE_MISSING":           {Severity: "Fatal"},
+	} {
+		_, err := diagnose.DiagnoseWithProfile(graph, builtins, &diagnose.Profile{Rules: map[string]diagnose.RuleOverride{name: bad}})
+		assert.Error(t, err, name)
+	}
+}
+
+// provisioningRule reports a site-specific condition in its own words.
+type provisioningRule struct{}
+
+func (r *provisioningRule) ID() string { return "SITE_PROVISIONING" }
+
+func (r *provisioningRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	return []types.FailureHint{{ID: r.ID(), Severity: types.SeverityInfo, Component: "Dataset",
+		Context: "Still initializing the site's storage quota."}}
+}
+
+func TestDiagnose_Silences(t *testing.T) {
+	now := time.Date(2026, time.January, 1, 10, 0, 0, 0, time.UTC)
+	silences, err := types.ParseSilences(`[
+		{"rule": "FUSE_MISSING", "reason": "fuse pool migration", "expires": "2026-02-01T00:00:00Z"},
+		{"rule": "WORKER_PARTIALLY_READY", "expires": "2025-12-01T00:00:00Z"}
+	]`)
+	assert.NoError(t, err)
+
+	graph := &types.ResourceGraph{
+		ObservedAt: now,
+		Dataset:    &types.DatasetInfo{Status: "Bound", Silences: silences},
+		Runtime: &types.RuntimeInfo{
+			Worker: &types.ComponentInfo{Ready: 2, Replicas: 3},
+			Fuse:   &types.ComponentInfo{Ready: 0, Replicas: 2},
+		},
+	}
+
+	result := diagnose.Diagnose(graph)
+
+	// The expired silence does not apply.
+	assert.False(t, result.IsHealthy)
+	assert.Len(t, result.FailureHints, 1)
+	assert.Equal(t, "WORKER_PARTIALLY_READY", result.FailureHints[0].ID)
+	assert.Len(t, result.Silenced, 1)
+	assert.Equal(t, "FUSE_MISSING", result.Silenced[0].ID)
+	assert.Equal(t, "fuse pool migration", result.Silenced[0].Reason)
+	assert.Contains(t, result.Summary, "1 silenced.")
+}
+
+func TestParseSilences(t *testing.T) {
+	silences, err := types.ParseSilences(" FUSE_MISSING, PVC_NOT_BOUND ")
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Silence{{RuleID: "FUSE_MISSING"}, {RuleID: "PVC_NOT_BOUND"}}, silences)
+
+	_, err = types.ParseSilences(`[{"reason": "no rule"}]`)
+	assert.Error(t, err)
+
+	// A malformed annotation is surfaced as a finding.
+	result := diagnose.Diagnose(&types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Status: "Bound", SilenceError: "invalid annotation"},
+		Runtime: &types.RuntimeInfo{},
+	})
+	assert.Len(t, result.FailureHints, 1)
+	assert.Equal(t, "SILENCE_ANNOTATION_INVALID", result.FailureHints[0].ID)
+}
+
+func TestDiagnose_CorrelationMatchesObjects(t *testing.T) {
+	oom := &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}
+	crashLoop := &corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}
+	evicted := func(name, node string) types.PodInfo {
+		return types.PodInfo{Name: name, Phase: "Failed", Reason: "Evicted", Message: "The node was low on resource: ephemeral-storage.", Node: node}
+	}
+	graph := &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Name: "demo-data", Namespace: "default", Status: "Bound"},
+		Runtime: &types.RuntimeInfo{
+			Name: "demo-data",
+			Type: "AlluxioRuntime",
+			TieredStore: []types.TieredStoreLevel{
+				{MediumType: "HDD", Paths: []string{"/mnt/hdd"}, Quota: resource.MustParse("50Gi")},
+			},
+			Master: &types.ComponentInfo{
+				Name: "demo-data-master", Ready: 0, Replicas: 1,
+				Pods: []types.PodInfo{{Name: "demo-data-master-0", Node: "node-1", Restarts: 3, Containers: []types.ContainerInfo{
+					{Name: "master", RestartCount: 3, State: crashLoop},
+				}}},
+			},
+			Worker: &types.ComponentInfo{
+				Name: "demo-data-worker", Ready: 1, Replicas: 4,
+				Pods: []types.PodInfo{
+					evicted("demo-data-worker-0", "node-1"),
+					{Name: "demo-data-worker-1", Ready: true, Node: "node-3", Containers: []types.ContainerInfo{{Name: "worker", Ready: true}}},
+					evicted("demo-data-worker-2", "node-3"),
+					{Name: "demo-data-worker-3", Node: "node-2", Restarts: 4, Containers: []types.ContainerInfo{
+						{Name: "worker", RestartCount: 4, MemoryLimit: "4Gi", State: crashLoop, LastState: oom},
+					}},
+				},
+			},
+			Fuse: &types.ComponentInfo{
+				Name: "demo-data-fuse", Ready: 1, Replicas: 1,
+				Pods: []types.PodInfo{{Name: "demo-data-fuse-abcde", Ready: true, Node: "node-2", Containers: []types.ContainerInfo{
+					{Name: "fuse", Ready: true, RestartCount: 1, MemoryLimit: "1Gi", LastState: oom},
+				}}},
+			},
+		},
+		Nodes: []types.NodeInfo{
+			{Name: "node-1", Ready: true},
+			{Name: "node-2", Ready: true},
+			{Name: "node-3", Ready: true, Pressures: []string{"DiskPressure"}},
+		},
+	}
+
+	result := diagnose.Diagnose(graph)
+
+	byPod := make(map[string]types.FailureHint)
+	for _, h := range result.FailureHints {
+		if h.Evidence.Kind == "Pod" {
+			byPod[h.ID+"/"+h.Evidence.Name] = h
+		}
+	}
+
+	// The fuse pod running out of memory does not explain the crashing master.
+	require.Contains(t, byPod, "POD_CRASHLOOP_BACKOFF/demo-data-master-0")
+	assert.Empty(t, byPod["POD_CRASHLOOP_BACKOFF/demo-data-master-0"].CausedBy)
+	assert.True(t, byPod["POD_CRASHLOOP_BACKOFF/demo-data-master-0"].RootCause)
+	// The worker's own OOM kills do.
+	assert.Equal(t, []string{"OOM_KILLED"}, byPod["POD_CRASHLOOP_BACKOFF/demo-data-worker-3"].CausedBy)
+
+	// Disk pressure on node-3 explains the eviction there, not the one on node-1.
+	require.Contains(t, byPod, "POD_EVICTED/demo-data-worker-0")
+	assert.Empty(t, byPod["POD_EVICTED/demo-data-worker-0"].CausedBy)
+	assert.Equal(t, []string{"TIEREDSTORE_DISK_PRESSURE"}, byPod["POD_EVICTED/demo-data-worker-2"].CausedBy)
+}
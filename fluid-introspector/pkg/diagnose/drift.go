@@ -0,0 +1,110 @@
+package diagnose
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RUNTIME_WORKLOAD_DRIFT
+// Reports each field the Runtime spec declares that its StatefulSet or DaemonSet does
+// not match: replicas, image, image tag and resource requests. The Fluid controller
+// propagates these fields; drift means it failed to, e.g. after a partial upgrade,
+// or that someone edited the workload directly.
+type RuntimeWorkloadDriftRule struct{}
+
+func (r *RuntimeWorkloadDriftRule) ID() string { return "RUNTIME_WORKLOAD_DRIFT" }
+
+func (r *RuntimeWorkloadDriftRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Runtime spec and workload drift",
+		Description: "A field declared in the Runtime spec (replicas, image, image tag, resource requests) differs from its StatefulSet/DaemonSet. One finding per field.",
+		Severity:    "Warning",
+		Component:   "Runtime components",
+		Checks:      "Replicas, image, image tag and resource requests declared in the Runtime spec against the StatefulSet or DaemonSet pod template.",
+		Causes:      []string{"The runtime controller failed to reconcile, e.g. after a partial upgrade", "The workload was edited by hand"},
+		Remediation: []string{"Check the runtime controller logs in fluid-system for reconcile errors", "Revert manual edits of the workload"},
+		Related:     []string{"IMAGE_VERSION_SKEW"},
+	}
+}
+
+func (r *RuntimeWorkloadDriftRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	if g.Runtime == nil {
+		return nil
+	}
+
+	var hints []types.FailureHint
+	for _, rc := range runtimeComponents(g.Runtime) {
+		desired, actual := rc.info.Desired, rc.info.Actual
+		if desired == nil || actual == nil {
+			continue
+		}
+		kind := workloadKind(rc)
+		for _, d := range specDrift(desired, actual) {
+			ev := factEvidence(kind, rc.info.Name, fmt.Sprintf("{field}: %s spec {desired}, %s {actual}", runtimeKind(g), kind),
+				fact("field", d.field), fact("desired", d.desired), fact("actual", d.actual))
+			ev.Objects = []types.ObjectRef{ref(kind, rc.info.Name), ref(runtimeKind(g), g.Runtime.Name)}
+			hints = append(hints, types.FailureHint{
+				ID:        r.ID(),
+				Severity:  types.SeverityWarning,
+				Component: rc.component,
+				Evidence:  ev,
+				Suggestion: fmt.Sprintf("The Fluid runtime controller has not applied the %s spec to the %s. Check the controller logs for reconcile errors; if the %s was edited by hand, revert the edit.",
+					runtimeKind(g), kind, kind),
+			})
+		}
+	}
+	return hints
+}
+
+// drift is a field whose declared and actual values differ.
+type drift struct {
+	field, desired, actual string
+}
+
+// specDrift compares the fields the desired spec declares with the actual spec, in a fixed order.
+func specDrift(desired, actual *types.ComponentSpec) []drift {
+	var out []drift
+	if desired.Replicas != nil && actual.Replicas != nil && *desired.Replicas != *actual.Replicas {
+		out = append(out, drift{"replicas", fmt.Sprint(*desired.Replicas), fmt.Sprint(*actual.Replicas)})
+	}
+	if desired.Image != "" && desired.Image != actual.Image {
+		out = append(out, drift{"image", desired.Image, orUnknown(actual.Image)})
+	}
+	if desired.ImageTag != "" && desired.ImageTag != actual.ImageTag {
+		out = append(out, drift{"imageTag", desired.ImageTag, orUnknown(actual.ImageTag)})
+	}
+
+	names := make([]string, 0, len(desired.Requests))
+	for name := range desired.Requests {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		want := desired.Requests[corev1.ResourceName(name)]
+		got, ok := actual.Requests[corev1.ResourceName(name)]
+		if !ok {
+			out = append(out, drift{"requests." + name, want.String(), "<unset>"})
+		} else if want.Cmp(got) != 0 {
+			out = append(out, drift{"requests." + name, want.String(), got.String()})
+		}
+	}
+	return out
+}
+
+// workloadKind names the kind of a component's workload. Without the object, masters
+// and workers are assumed to be StatefulSets and fuse a DaemonSet, as Fluid deploys them.
+func workloadKind(rc runtimeComponent) string {
+	switch {
+	case rc.info.StatefulSet != nil:
+		return "StatefulSet"
+	case rc.info.DaemonSet != nil:
+		return "DaemonSet"
+	case rc.component == "Runtime/Fuse":
+		return "DaemonSet"
+	default:
+		return "StatefulSet"
+	}
+}
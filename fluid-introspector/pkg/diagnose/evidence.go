@@ -0,0 +1,180 @@
+package diagnose
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Findings reference the objects they are about, so tools can link them back to the
+// exact objects observed. Rules name objects by kind and name, and may add further
+// objects with ref; the engine resolves API versions, namespaces, UIDs and resource
+// versions from the graph.
+
+const fluidAPIVersion = "data.fluid.io/v1alpha1"
+
+// apiVersions of the kinds evidence names. Runtime kinds, e.g. AlluxioRuntime, are
+// resolved by their suffix.
+var apiVersions = map[string]string{
+	"Pod":                   "v1",
+	"Node":                  "v1",
+	"PersistentVolumeClaim": "v1",
+	"PersistentVolume":      "v1",
+	"Secret":                "v1",
+	"Namespace":             "v1",
+	"StatefulSet":           "apps/v1",
+	"DaemonSet":             "apps/v1",
+	"Deployment":            "apps/v1",
+	"Dataset":               fluidAPIVersion,
+	"ThinRuntimeProfile":    fluidAPIVersion,
+}
+
+var clusterScoped = map[string]bool{"Node": true, "PersistentVolume": true, "Namespace": true, "ThinRuntimeProfile": true}
+
+// apiVersion returns the API version of kind, or "" if kind names no object, like
+// the ResourceGraph of an engine finding or the "Runtime" a Dataset lacks.
+func apiVersion(kind string) string {
+	if v, ok := apiVersions[kind]; ok {
+		return v
+	}
+	if kind != "Runtime" && strings.HasSuffix(kind, "Runtime") {
+		return fluidAPIVersion
+	}
+	return ""
+}
+
+// ref names an object of the Dataset's namespace, or a cluster-scoped one, for
+// Evidence.Objects.
+func ref(kind, name string) types.ObjectRef {
+	return types.ObjectRef{Kind: kind, Name: name}
+}
+
+// podRef references a pod of any namespace, e.g. another Dataset's.
+func podRef(namespace string, p types.PodInfo) types.ObjectRef {
+	r := types.ObjectRef{APIVersion: "v1", Kind: "Pod", Namespace: namespace, Name: p.Name}
+	if p.Object != nil {
+		r.UID, r.ResourceVersion = string(p.Object.UID), p.Object.ResourceVersion
+	}
+	return r
+}
+
+// resolveEvidence lists the primary object first in the objects of each finding,
+// completes every object reference and renders the detail of findings that only
+// report facts.
+func resolveEvidence(g *types.ResourceGraph, hints []types.FailureHint) {
+	for i := range hints {
+		ev := &hints[i].Evidence
+		if ev.Detail == "" && len(ev.Facts) > 0 {
+			ev.Detail = renderFacts(ev.Facts)
+		}
+		if ev.Name != "" && apiVersion(ev.Kind) != "" &&
+			(len(ev.Objects) == 0 || ev.Objects[0].Kind != ev.Kind || ev.Objects[0].Name != ev.Name) {
+			ev.Objects = append([]types.ObjectRef{ref(ev.Kind, ev.Name)}, ev.Objects...)
+		}
+		for j := range ev.Objects {
+			completeRef(g, &ev.Objects[j])
+		}
+	}
+}
+
+// fact reports an observed value for Evidence.Facts.
+func fact(key string, value interface{}) types.Fact {
+	return types.Fact{Key: key, Value: fmt.Sprint(value)}
+}
+
+// factEvidence reports the observed values of a finding as facts, with the detail
+// rendered from format, see renderDetail.
+func factEvidence(kind, name, format string, facts ...types.Fact) types.Evidence {
+	return types.Evidence{Kind: kind, Name: name, Detail: renderDetail(format, facts), Facts: facts}
+}
+
+// renderDetail replaces each {key} in format with the value of that fact, so the
+// detail of a finding says what its facts report, e.g. "Ready replicas: {readyReplicas}/{replicas}".
+func renderDetail(format string, facts []types.Fact) string {
+	pairs := make([]string, 0, 2*len(facts))
+	for _, f := range facts {
+		pairs = append(pairs, "{"+f.Key+"}", f.Value)
+	}
+	return strings.NewReplacer(pairs...).Replace(format)
+}
+
+// renderFacts renders facts as "key=value" pairs, in order.
+func renderFacts(facts []types.Fact) string {
+	parts := make([]string, 0, len(facts))
+	for _, f := range facts {
+		parts = append(parts, f.Key+"="+f.Value)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// completeRef fills in what a rule left out of a reference. UID and resource version
+// are only known for objects of the graph.
+func completeRef(g *types.ResourceGraph, r *types.ObjectRef) {
+	if r.APIVersion == "" {
+		r.APIVersion = apiVersion(r.Kind)
+	}
+	if r.Namespace == "" && !clusterScoped[r.Kind] {
+		r.Namespace = g.Dataset.Namespace
+	}
+	if r.UID != "" || (r.Namespace != g.Dataset.Namespace && !clusterScoped[r.Kind]) {
+		return
+	}
+	if obj := graphObject(g, r.Kind, r.Name); obj != nil {
+		r.UID, r.ResourceVersion = string(obj.GetUID()), obj.GetResourceVersion()
+	}
+}
+
+// graphObject finds the object of the graph with the kind and name, or nil.
+func graphObject(g *types.ResourceGraph, kind, name string) metav1.Object {
+	switch kind {
+	case "Dataset":
+		if g.Dataset.Name == name {
+			return g.Dataset.Object
+		}
+	case "Pod":
+		if p, ok := findPod(g, name); ok && p.Object != nil {
+			return p.Object
+		}
+		for _, p := range g.Consumers {
+			if p.Name == name && p.Object != nil {
+				return p.Object
+			}
+		}
+	case "StatefulSet", "DaemonSet":
+		if g.Runtime == nil {
+			return nil
+		}
+		for _, rc := range runtimeComponents(g.Runtime) {
+			if rc.info.Name != name {
+				continue
+			}
+			if kind == "StatefulSet" && rc.info.StatefulSet != nil {
+				return rc.info.StatefulSet
+			}
+			if kind == "DaemonSet" && rc.info.DaemonSet != nil {
+				return rc.info.DaemonSet
+			}
+		}
+	case "Node":
+		for _, n := range g.Nodes {
+			if n.Name == name && n.Object != nil {
+				return n.Object
+			}
+		}
+	case "PersistentVolumeClaim":
+		if g.Infrastructure != nil && g.Infrastructure.PVC != nil && g.Infrastructure.PVC.Name == name && g.Infrastructure.PVC.Object != nil {
+			return g.Infrastructure.PVC.Object
+		}
+	case "PersistentVolume":
+		if g.Infrastructure != nil && g.Infrastructure.PV != nil && g.Infrastructure.PV.Name == name && g.Infrastructure.PV.Object != nil {
+			return g.Infrastructure.PV.Object
+		}
+	default:
+		if g.Runtime != nil && g.Runtime.Type == kind && g.Runtime.Name == name {
+			return g.Runtime.Object
+		}
+	}
+	return nil
+}
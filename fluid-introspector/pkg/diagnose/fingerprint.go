@@ -0,0 +1,73 @@
+package diagnose
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+)
+
+// Fingerprint returns a stable hash of the set of problems the findings describe:
+// their rule ID, component, the kind and name of their evidence, the facts telling
+// problems about the same object apart (see identityFacts) and the other objects
+// involved, such as the peer of a conflict. Severity, details such as restart counts,
+// the UIDs and resource versions of the evidence objects, order and duplicates do not
+// change it, so it changes only when a problem appears or disappears; a recreated pod
+// keeps it. It is the lowercase hex SHA-256 of the sorted, distinct identities, one
+// per line.
+func Fingerprint(hints []types.FailureHint) string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, h := range hints {
+		key := strings.Join(identity(h), "\x00")
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	sum := sha256.New()
+	for _, k := range keys {
+		sum.Write([]byte(k))
+		sum.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// identityFacts are the fact keys naming which problem a finding reports about its
+// evidence object: the drifted field, the tiered store medium over a node's capacity,
+// the failing container of a pod, the conflicting host paths or ports, or the mount,
+// its option or the encrypt option at fault.
+var identityFacts = map[string]bool{
+	"field":         true,
+	"medium":        true,
+	"container":     true,
+	"path":          true,
+	"peerPath":      true,
+	"ports":         true,
+	"mount":         true,
+	"option":        true,
+	"encryptOption": true,
+}
+
+// identity returns the parts of a finding that name its problem.
+func identity(h types.FailureHint) []string {
+	parts := []string{h.ID, h.Component, h.Evidence.Kind, h.Evidence.Name}
+	for _, f := range h.Evidence.Facts {
+		if identityFacts[f.Key] {
+			parts = append(parts, f.Key+"="+f.Value)
+		}
+	}
+	var objects []string
+	for _, o := range h.Evidence.Objects {
+		if o.Kind == h.Evidence.Kind && o.Name == h.Evidence.Name {
+			continue
+		}
+		objects = append(objects, o.Kind+"/"+o.Namespace+"/"+o.Name)
+	}
+	sort.Strings(objects)
+	return append(parts, objects...)
+}
@@ -0,0 +1,128 @@
+package diagnose
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+)
+
+// onDemandFuseLabelPrefix starts the node label, fluid.io/f-<namespace>-<name>, that the
+// fuse DaemonSet of an on-demand fuse selects. Fluid labels a node when a pod mounting
+// the Dataset is placed there, so the fuse runs only where it is used.
+const onDemandFuseLabelPrefix = "fluid.io/f-"
+
+// onDemandFuseLabel returns the fuse node selector label marking an on-demand fuse, if
+// any; the first in sort order should there be several.
+func onDemandFuseLabel(fuse *types.ComponentInfo) (string, bool) {
+	label := ""
+	for k := range fuse.NodeSelector {
+		if strings.HasPrefix(k, onDemandFuseLabelPrefix) && (label == "" || k < label) {
+			label = k
+		}
+	}
+	return label, label != ""
+}
+
+// consumerHints reports each node running pods that mount the Dataset but no ready
+// fuse pod. Fuse pods on nodes without such pods, and pods not yet placed, are ignored.
+func (r *FuseMissingRule) consumerHints(g *types.ResourceGraph, fuse *types.ComponentInfo) []types.FailureHint {
+	byNode := make(map[string][]types.PodInfo)
+	var nodes []string
+	for _, p := range g.Consumers {
+		if p.Node == "" || p.Phase == "Succeeded" || p.Phase == "Failed" {
+			continue
+		}
+		if _, ok := byNode[p.Node]; !ok {
+			nodes = append(nodes, p.Node)
+		}
+		byNode[p.Node] = append(byNode[p.Node], p)
+	}
+	sort.Strings(nodes)
+
+	var hints []types.FailureHint
+	for _, node := range nodes {
+		fusePod, ready := fuseOnNode(fuse, node)
+		if ready {
+			continue
+		}
+		consumers := byNode[node]
+		var names []string
+		var waiting time.Time
+		for _, p := range consumers {
+			names = append(names, p.Name)
+			if waiting.IsZero() || p.CreationTimestamp.Before(waiting) {
+				waiting = p.CreationTimestamp
+			}
+		}
+
+		cause, suggestion := fuseNodeCause(g, fuse, node, fusePod)
+		severity, context := r.Escalation.severity(g, types.SeverityWarning, waiting)
+		objects := []types.ObjectRef{ref("Node", node)}
+		if fusePod != nil {
+			objects = append(objects, ref("Pod", fusePod.Name))
+		}
+		for _, p := range consumers {
+			objects = append(objects, ref("Pod", p.Name))
+		}
+		hint := types.FailureHint{
+			ID:         r.ID(),
+			Severity:   severity,
+			Component:  "Runtime/Fuse",
+			Evidence:   factEvidence("Node", node, cause+"; pods mounting the Dataset: {consumers}", fact("consumers", strings.Join(names, ", "))),
+			Suggestion: suggestion,
+			Context:    context,
+		}
+		hint.Evidence.Objects = objects
+		hints = append(hints, hint)
+	}
+	return hints
+}
+
+// fuseOnNode returns the fuse pod on node, preferring a ready one.
+func fuseOnNode(fuse *types.ComponentInfo, node string) (*types.PodInfo, bool) {
+	var found *types.PodInfo
+	for i, p := range fuse.Pods {
+		if p.Node != node {
+			continue
+		}
+		if p.Ready {
+			return &fuse.Pods[i], true
+		}
+		found = &fuse.Pods[i]
+	}
+	return found, false
+}
+
+// fuseNodeCause explains why node has no ready fuse pod: the pod is unready, the node
+// lacks the on-demand label or does not match the fuse node selector.
+func fuseNodeCause(g *types.ResourceGraph, fuse *types.ComponentInfo, node string, fusePod *types.PodInfo) (string, string) {
+	if fusePod != nil {
+		return fmt.Sprintf("Fuse pod %s not ready (%s)", fusePod.Name, podEvidence(*fusePod).Detail),
+			fmt.Sprintf("Pods mounting the Dataset on this node cannot read until the fuse is ready. Check the fuse pod: kubectl describe pod %s%s", fusePod.Name, namespaceFlag(g))
+	}
+
+	labels, known := nodeLabels(g, node)
+	if label, ok := onDemandFuseLabel(fuse); ok && (!known || labels[label] != fuse.NodeSelector[label]) {
+		return fmt.Sprintf("No fuse pod on node %s; node lacks on-demand fuse label %s", node, label),
+			fmt.Sprintf("The Fluid CSI plugin labels the node when a pod mounting the Dataset is placed there, and the fuse follows. Check the csi-nodeplugin-fluid pod on %s: kubectl logs -n fluid-system <csi-nodeplugin-fluid pod> -c plugins", node)
+	}
+	if known && !labelsMatch(labels, fuse.NodeSelector) {
+		return fmt.Sprintf("No fuse pod on node %s; node does not match fuse nodeSelector %s", node, formatSelector(fuse.NodeSelector)),
+			fmt.Sprintf("Widen spec.fuse.nodeSelector of the %s to the application's nodes, or schedule the application onto nodes matching it.", runtimeKind(g))
+	}
+	return fmt.Sprintf("No fuse pod on node %s", node),
+		fmt.Sprintf("The %s DaemonSet does not run on the node. Check its tolerations against the node's taints: kubectl describe node %s", fuse.Name, node)
+}
+
+// nodeLabels returns the labels of node from the inventory, if it is known.
+func nodeLabels(g *types.ResourceGraph, node string) (map[string]string, bool) {
+	for _, n := range g.Nodes {
+		if n.Name == node {
+			return n.Labels, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,367 @@
+package diagnose_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/diagnose"
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// graphBuilder derives a ResourceGraph from fuzz input, one decision per byte. Past
+// the end of the input every decision is zero, so any input yields a finite graph.
+type graphBuilder struct {
+	data []byte
+}
+
+func (b *graphBuilder) next() byte {
+	if len(b.data) == 0 {
+		return 0
+	}
+	v := b.data[0]
+	b.data = b.data[1:]
+	return v
+}
+
+func (b *graphBuilder) bool() bool { return b.next()%2 == 1 }
+
+func (b *graphBuilder) int32(n int) int32 { return int32(int(b.next()) % n) }
+
+func (b *graphBuilder) pick(choices ...string) string {
+	return choices[int(b.next())%len(choices)]
+}
+
+func (b *graphBuilder) time() time.Time {
+	if !b.bool() {
+		return time.Time{}
+	}
+	return time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(b.next()) * time.Minute)
+}
+
+func (b *graphBuilder) quantity() resource.Quantity {
+	return resource.MustParse(b.pick("0", "1", "512Mi", "4Gi", "16Gi", "1Ti", "100m"))
+}
+
+func (b *graphBuilder) resources() corev1.ResourceList {
+	if !b.bool() {
+		return nil
+	}
+	return corev1.ResourceList{
+		corev1.ResourceName(b.pick("memory", "cpu", "ephemeral-storage")): b.quantity(),
+	}
+}
+
+func (b *graphBuilder) node() string { return b.pick("", "node-1", "node-2", "node-3") }
+
+func (b *graphBuilder) labels() map[string]string {
+	if !b.bool() {
+		return nil
+	}
+	return map[string]string{b.pick("fluid.io/f-default-demo-data", "zone", "pool"): b.pick("true", "a", "")}
+}
+
+func (b *graphBuilder) containerState() *corev1.ContainerState {
+	switch b.next() % 4 {
+	case 1:
+		return &corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+			Reason: b.pick("", "CrashLoopBackOff", "ImagePullBackOff", "ErrImagePull", "CreateContainerConfigError"),
+		}}
+	case 2:
+		return &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Reason: b.pick("", "OOMKilled", "Error", "Completed"), ExitCode: b.int32(140),
+		}}
+	case 3:
+		return &corev1.ContainerState{}
+	}
+	return nil
+}
+
+func (b *graphBuilder) pod(name string) types.PodInfo {
+	p := types.PodInfo{
+		Name:              name,
+		Status:            b.pick("Running", "Pending", "CrashLoopBackOff", ""),
+		Phase:             b.pick("Running", "Pending", "Failed", "Succeeded", ""),
+		Reason:            b.pick("", "Evicted"),
+		Ready:             b.bool(),
+		Node:              b.node(),
+		Restarts:          b.int32(20),
+		NodeSelector:      b.labels(),
+		Requests:          b.resources(),
+		CreationTimestamp: b.time(),
+		Unschedulable: b.pick("", "garbage", "0/3 nodes are available: 2 Insufficient memory, 1 node(s) had untolerated taint {a: b}.",
+			"0/2 nodes are available: 2 node(s) didn't match Pod's node affinity/selector. preemption: x"),
+	}
+	if b.bool() {
+		p.HostPorts = []int32{19998 + b.int32(3)}
+	}
+	if b.bool() {
+		p.HostPaths = []string{b.pick("/dev/fuse", "/mnt/cache", "/mnt/cache/a", "/", "")}
+	}
+	for i := 0; i < int(b.next()%3); i++ {
+		p.Containers = append(p.Containers, types.ContainerInfo{
+			Name:         fmt.Sprintf("c%d", i),
+			Image:        b.pick("", "alluxio/alluxio:2.9.2", "alluxio/alluxio:2.9.0", "registry:5000/img", "img@sha256:abc"),
+			Init:         b.bool(),
+			Ready:        b.bool(),
+			RestartCount: b.int32(10),
+			MemoryLimit:  b.pick("", "4Gi", "bogus"),
+			State:        b.containerState(),
+			LastState:    b.containerState(),
+		})
+	}
+	return p
+}
+
+func (b *graphBuilder) spec() *types.ComponentSpec {
+	if !b.bool() {
+		return nil
+	}
+	s := &types.ComponentSpec{Image: b.pick("", "alluxio/alluxio"), ImageTag: b.pick("", "2.9.2", "2.9.0"), Requests: b.resources()}
+	if b.bool() {
+		n := b.int32(4)
+		s.Replicas = &n
+	}
+	return s
+}
+
+func (b *graphBuilder) component(name string) *types.ComponentInfo {
+	if !b.bool() {
+		return nil
+	}
+	c := &types.ComponentInfo{
+		Name:               name,
+		Replicas:           b.int32(4),
+		Ready:              b.int32(4),
+		State:              b.pick("Ready", "PartialReady", "NotReady", "ComponentsScaledDown"),
+		NodeSelector:       b.labels(),
+		Desired:            b.spec(),
+		Actual:             b.spec(),
+		CreationTimestamp:  b.time(),
+		LastTransitionTime: b.time(),
+	}
+	for i := 0; i < int(b.next()%4); i++ {
+		c.Pods = append(c.Pods, b.pod(fmt.Sprintf("%s-%d", name, i)))
+	}
+	return c
+}
+
+func (b *graphBuilder) graph(name string) *types.ResourceGraph {
+	g := &types.ResourceGraph{ObservedAt: b.time()}
+	if b.next()%8 != 0 {
+		g.Dataset = &types.DatasetInfo{
+			Name:              name,
+			Namespace:         b.pick("", "default"),
+			Status:            b.pick("Bound", "NotBound", ""),
+			Phase:             b.pick("Bound", "Pending", ""),
+			Labels:            b.labels(),
+			CreationTimestamp: b.time(),
+		}
+		if b.bool() {
+			g.Dataset.Silences = []types.Silence{{RuleID: b.pick("FUSE_MISSING", "MASTER_NOT_READY", ""), Expires: b.time()}}
+		}
+		for i := 0; i < int(b.next()%3); i++ {
+			m := types.MountInfo{MountPoint: b.pick("juicefs:///", "oss://bucket/data", "s3://bucket", ""), OptionKeys: []string{b.pick("metaurl", "fs.oss.endpoint", "fs.oss.accessKeyId", "")}}
+			if b.bool() {
+				m.EncryptOptions = []types.EncryptOption{{Name: b.pick("metaurl", "token", "fs.oss.accessKeySecret", ""), SecretName: b.pick("creds", ""), SecretKey: b.pick("metaurl", "")}}
+			}
+			g.Dataset.Mounts = append(g.Dataset.Mounts, m)
+		}
+	}
+	if b.bool() {
+		g.Secrets = []types.SecretInfo{}
+		if b.bool() {
+			g.Secrets = append(g.Secrets, types.SecretInfo{Name: "creds", Found: b.bool(), Keys: []string{b.pick("metaurl", "other")}})
+		}
+	}
+	if b.bool() {
+		g.Runtime = &types.RuntimeInfo{
+			Name:   name,
+			Type:   b.pick("AlluxioRuntime", "JindoRuntime", "JuiceFSRuntime", "ThinRuntime", ""),
+			Master: b.component(name + "-master"),
+			Worker: b.component(name + "-worker"),
+			Fuse:   b.component(name + "-fuse"),
+		}
+		if b.bool() {
+			found := b.bool()
+			g.Runtime.ProfileName, g.Runtime.ProfileFound = b.pick("default", ""), &found
+		}
+		for i := 0; i < int(b.next()%3); i++ {
+			g.Runtime.TieredStore = append(g.Runtime.TieredStore, types.TieredStoreLevel{
+				MediumType: b.pick("MEM", "SSD", "HDD", ""),
+				Paths:      []string{b.pick("/dev/shm", "/mnt/cache", "")},
+				Quota:      b.quantity(),
+			})
+		}
+	}
+	if b.bool() {
+		g.Infrastructure = &types.InfrastructureInfo{}
+		if b.bool() {
+			g.Infrastructure.PVC = &types.PVCInfo{Name: name, Status: b.pick("Bound", "Pending", ""), CreationTimestamp: b.time()}
+		}
+	}
+	for i := 0; i < int(b.next()%4); i++ {
+		n := types.NodeInfo{
+			Name:          fmt.Sprintf("node-%d", i+1),
+			Ready:         b.bool(),
+			Unschedulable: b.bool(),
+			Labels:        b.labels(),
+			Allocatable:   b.resources(),
+		}
+		if b.bool() {
+			n.Pressures = []string{b.pick("DiskPressure", "MemoryPressure")}
+		}
+		if b.bool() {
+			n.Taints = []corev1.Taint{{Key: "k", Effect: corev1.TaintEffect(b.pick("NoSchedule", "PreferNoSchedule", ""))}}
+		}
+		g.Nodes = append(g.Nodes, n)
+	}
+	for i := 0; i < int(b.next()%3); i++ {
+		g.Events = append(g.Events, types.EventInfo{
+			Kind: "Pod", Name: fmt.Sprintf("%s-worker-%d", name, i), Reason: "FailedScheduling",
+			Message: b.pick("", "0/1 nodes are available: 1 Insufficient cpu."),
+		})
+	}
+	if b.bool() {
+		g.Consumers = []types.PodInfo{}
+		for i := 0; i < int(b.next()%3); i++ {
+			g.Consumers = append(g.Consumers, b.pod(fmt.Sprintf("app-%d", i)))
+		}
+	}
+	for i := 0; i < int(b.next()%3); i++ {
+		g.Controllers = append(g.Controllers, types.ControllerInfo{
+			Name:   fmt.Sprintf("controller-%d", i),
+			Images: []string{b.pick("fluidcloudnative/dataset-controller:v1.0.0", "fluidcloudnative/fluid-csi:v0.9.0", "sidecar", "")},
+		})
+	}
+	return g
+}
+
+// logs samples a log for some of the runtime pods of g: known failure messages mixed
+// with raw fuzz input, which need not be valid UTF-8.
+func (b *graphBuilder) logs(g *types.ResourceGraph) map[string]string {
+	logs := map[string]string{}
+	if g.Runtime == nil {
+		return logs
+	}
+	for _, c := range []*types.ComponentInfo{g.Runtime.Master, g.Runtime.Worker, g.Runtime.Fuse} {
+		if c == nil {
+			continue
+		}
+		for _, p := range c.Pods {
+			if !b.bool() {
+				continue
+			}
+			var lines []string
+			for i := 0; i < int(b.next()%4); i++ {
+				lines = append(lines, b.pick("",
+					"fuse: transport endpoint is not connected",
+					"java.net.SocketTimeoutException: connect timed out to oss-cn-hangzhou",
+					"ERROR 403 Forbidden: access denied",
+					"KrbException: Clock skew too great",
+					"load setting: dial tcp 10.0.0.1:6379: connect: connection refused",
+					"ufs path /data does not exist",
+					string(b.bytes(int(b.next()%64))),
+				))
+			}
+			logs[p.Name] = strings.Join(lines, "\n")
+		}
+	}
+	return logs
+}
+
+func (b *graphBuilder) bytes(n int) []byte {
+	if n > len(b.data) {
+		n = len(b.data)
+	}
+	v := b.data[:n]
+	b.data = b.data[n:]
+	return v
+}
+
+// fuzzRuleSpecs are declarative rules, one of which joins the built-ins on each input.
+// Their conditions guard the fields their evidence templates read.
+var fuzzRuleSpecs = []diagnose.RuleSpec{
+	{
+		ID: "FUZZ_WORKER_NOT_REDUNDANT", Severity: types.SeverityWarning, Component: "Runtime/Worker",
+		Condition: "has(graph.runtime) && has(graph.runtime.worker) && graph.runtime.worker.replicas < 2",
+		Evidence:  diagnose.EvidenceSpec{Kind: "StatefulSet", Name: "{{ .runtime.worker.name }}", Detail: "Replicas: {{ .runtime.worker.replicas }}"},
+	},
+	{
+		ID: "FUZZ_OWNER_LABEL_MISSING", Severity: types.SeverityInfo, Component: "Dataset",
+		Condition: "!has(graph.dataset.labels) || !('team' in graph.dataset.labels)",
+		Evidence:  diagnose.EvidenceSpec{Kind: "Dataset", Name: "{{ .dataset.name }}", Detail: "No team label"},
+		CausedBy:  []string{"DATASET_NOT_BOUND"},
+	},
+	{
+		ID: "FUZZ_UNSCHEDULABLE_NODE", Severity: types.SeverityCritical, Component: "Infrastructure/Node",
+		Condition: "has(graph.nodes) && graph.nodes.exists(n, has(n.unschedulable) && n.unschedulable)",
+		Evidence:  diagnose.EvidenceSpec{Kind: "Node", Name: "{{ range .nodes }}{{ .name }} {{ end }}", Detail: "{{ printf \"%d nodes\" (len .nodes) }}"},
+	},
+}
+
+// FuzzRules feeds random graphs and pod logs straight through every built-in rule and
+// one declarative rule, outside the engine's panic recovery, then through the full
+// pipeline, single and as a fleet.
+func FuzzRules(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1})
+	f.Add([]byte("\x07\x01\x01\x02\x01\x03\x01\x01\x03\x02\x01\x02\x03\x01\x01\x01\x02\x02\x01\x03\x03\x01\x02"))
+	f.Add([]byte{255, 3, 2, 1, 0, 9, 8, 7, 6, 5, 4, 3, 2, 1, 0, 255, 254, 253, 252, 251, 250, 249, 248, 247})
+	for seed := 0; seed < 16; seed++ {
+		data := make([]byte, 256)
+		for i := range data {
+			data[i] = byte((i*31 + seed*17) ^ (i >> 2))
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		b := &graphBuilder{data: data}
+		g := b.graph("demo-data")
+		peer := b.graph("other-data")
+		logs := b.logs(g)
+		for name, log := range b.logs(peer) {
+			logs[name] = log
+		}
+		declarative, err := diagnose.NewDeclarativeRule(fuzzRuleSpecs[int(b.next())%len(fuzzRuleSpecs)])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, rule := range append(diagnose.Rules(), declarative) {
+			if g.Dataset == nil {
+				continue // Rejected by validation before any rule runs
+			}
+			rule.Evaluate(g)
+			if lr, ok := rule.(diagnose.LogRule); ok {
+				lr.EvaluateLogs(g, logs)
+			}
+			if cr, ok := rule.(diagnose.ClusterRule); ok {
+				cr.EvaluateCluster(g, []*types.ResourceGraph{g, peer, nil})
+			}
+		}
+
+		set, _ := diagnose.DefaultRegistry.RuleSet()
+		if set, err = set.With(declarative); err != nil {
+			t.Fatal(err)
+		}
+		results, err := diagnose.DiagnoseFleet([]*types.ResourceGraph{g, peer}, set, nil, diagnose.WithTrace(), diagnose.WithPodLogs(logs))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range results {
+			if r != nil && len(r.Trace) != len(set) {
+				t.Errorf("traced %d of %d rules", len(r.Trace), len(set))
+			}
+			for _, h := range r.FailureHints {
+				if h.ID == diagnose.RuleErrorID {
+					t.Errorf("%s: %s", h.Evidence.Name, h.Evidence.Detail)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package diagnose
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+)
+
+// Escalation describes how the severity of a finding grows with the age of the
+// condition that triggered it. A Pending PVC is normal for a few seconds after
+// creation, but critical once it has been stuck for minutes.
+//
+// The zero value disables escalation: the rule always reports its base severity.
+type Escalation struct {
+	GracePeriod   time.Duration       // Younger conditions are reported as Info "still initializing" hints
+	CriticalAfter time.Duration       // Critical rules are capped at Warning until this age is reached
+	Severity      types.SeverityLevel // Replaces the rule's base severity if set, e.g. by a profile
+}
+
+// DefaultEscalation is used by the built-in rules.
+var DefaultEscalation = Escalation{
+	GracePeriod:   2 * time.Minute,
+	CriticalAfter: 5 * time.Minute,
+}
+
+// severity computes the effective severity of a finding whose condition has held
+// since the given time. It also returns a context sentence explaining the decision.
+// If the age is unknown (no snapshot time or no timestamp), base is returned unchanged.
+func (e Escalation) severity(g *types.ResourceGraph, base types.SeverityLevel, since time.Time) (types.SeverityLevel, string) {
+	base = e.base(base)
+	if e.GracePeriod <= 0 && e.CriticalAfter <= 0 {
+		return base, ""
+	}
+	if since.IsZero() || g.ObservedAt.IsZero() {
+		return base, ""
+	}
+
+	age := g.ObservedAt.Sub(since)
+	if age < 0 {
+		age = 0
+	}
+	age = age.Round(time.Second)
+
+	if age < e.GracePeriod {
+		return types.SeverityInfo, fmt.Sprintf("Still initializing: condition has held for %s, escalates after %s.", age, e.GracePeriod)
+	}
+	if base == types.SeverityCritical && age < e.CriticalAfter {
+		return types.SeverityWarning, fmt.Sprintf("Condition has held for %s, becomes Critical after %s.", age, e.CriticalAfter)
+	}
+	return base, fmt.Sprintf("Condition has held for %s.", age)
+}
+
+// base returns the severity a rule reports before escalation: sev, unless replaced.
+func (e Escalation) base(sev types.SeverityLevel) types.SeverityLevel {
+	if e.Severity != "" {
+		return e.Severity
+	}
+	return sev
+}
+
+// since returns the moment a resource entered its current state: the latest
+// known transition, falling back to its creation time.
+func since(created, transitioned time.Time) time.Time {
+	if transitioned.After(created) {
+		return transitioned
+	}
+	return created
+}
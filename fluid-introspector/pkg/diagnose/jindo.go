@@ -0,0 +1,51 @@
+package diagnose
+
+import "github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+
+// Jindo reads OSS credentials from these mount options, set through encryptOptions,
+// unless the mount configures a credentials provider, e.g. the instance's RAM role.
+var jindoOSSCredentialKeys = []string{"fs.oss.accessKeyId", "fs.oss.accessKeySecret"}
+
+const jindoOSSCredentialsProvider = "fs.oss.credentials.provider"
+
+// JINDO_OSS_CONFIG_INVALID
+// An oss:// mount of a JindoRuntime needs the bucket's endpoint as the fs.oss.endpoint
+// option and, unless a credentials provider is configured, an AccessKey pair read
+// from a Secret.
+type JindoOSSConfigInvalidRule struct{}
+
+func (r *JindoOSSConfigInvalidRule) ID() string { return "JINDO_OSS_CONFIG_INVALID" }
+
+func (r *JindoOSSConfigInvalidRule) RuntimeTypes() []string { return []string{"JindoRuntime"} }
+
+func (r *JindoOSSConfigInvalidRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Jindo OSS mount misconfigured",
+		Description: "JindoRuntime only: an oss:// mount's credential Secret or key is missing or its AccessKey pair is incomplete (Critical), it lacks fs.oss.endpoint, or its AccessKey is a plain option (Warning).",
+		Severity:    "Critical/Warning",
+		Component:   "Dataset",
+		Checks:      "The options and encryptOptions of each oss:// mount of the Dataset, and the Secret keys fs.oss.accessKeyId and fs.oss.accessKeySecret read from, unless fs.oss.credentials.provider is set. Only key names are read, never values.",
+		Causes:      []string{"The AccessKey Secret is missing or in another namespace", "Only one of fs.oss.accessKeyId and fs.oss.accessKeySecret set", "The endpoint left out, e.g. oss-cn-hangzhou-internal.aliyuncs.com", "The AccessKey given in options"},
+		Remediation: []string{"kubectl describe secret <secret> -n <ns>", "Add fs.oss.endpoint to the mount options", "Move fs.oss.accessKeyId and fs.oss.accessKeySecret to encryptOptions"},
+		Related:     []string{"DATASET_NOT_BOUND", "POD_CRASHLOOP_BACKOFF"},
+	}
+}
+
+func (r *JindoOSSConfigInvalidRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	var hints []types.FailureHint
+	for _, m := range mountsWithScheme(g, "oss") {
+		if !hasOptionKey(m, jindoOSSCredentialsProvider) {
+			hints = append(hints, credentialHints(g, r.ID(), m, jindoOSSCredentialKeys)...)
+		}
+		if !hasOptionKey(m, "fs.oss.endpoint") {
+			hints = append(hints, types.FailureHint{
+				ID:         r.ID(),
+				Severity:   types.SeverityWarning,
+				Component:  "Dataset",
+				Evidence:   factEvidence("Dataset", g.Dataset.Name, "Mount {mount}: no fs.oss.endpoint option", fact("mount", mountName(m))),
+				Suggestion: "Set the bucket's endpoint in the mount options, e.g. fs.oss.endpoint: oss-cn-hangzhou-internal.aliyuncs.com; without it Jindo cannot reach the bucket.",
+			})
+		}
+	}
+	return hints
+}
@@ -0,0 +1,109 @@
+package diagnose
+
+import (
+	"fmt"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+)
+
+// JUICEFS_METAURL_INVALID
+// JuiceFS has no master: workers and fuse connect to the metadata engine (Redis, MySQL,
+// TiKV, ...) whose URL the juicefs:/// mount reads from a Secret through the metaurl
+// encrypt option; the enterprise edition uses a token instead. Without it the runtime
+// cannot format or mount the volume.
+type JuiceFSMetaURLInvalidRule struct{}
+
+func (r *JuiceFSMetaURLInvalidRule) ID() string { return "JUICEFS_METAURL_INVALID" }
+
+func (r *JuiceFSMetaURLInvalidRule) RuntimeTypes() []string { return []string{"JuiceFSRuntime"} }
+
+func (r *JuiceFSMetaURLInvalidRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "JuiceFS metadata engine URL missing",
+		Description: "JuiceFSRuntime only: a juicefs:// mount reads no metaurl (or enterprise token) from a Secret, the Secret or key is missing (Critical), or the URL is a plain option (Warning).",
+		Severity:    "Critical/Warning",
+		Component:   "Dataset",
+		Checks:      "The encryptOptions and option keys of each juicefs:// mount of the Dataset, and the referenced Secret keys. Only key names are read, never values.",
+		Causes:      []string{"The Secret was created in another namespace than the Dataset", "A typo in valueFrom.secretKeyRef.key", "The metaurl given in options, where everyone reading the Dataset sees the password"},
+		Remediation: []string{"kubectl describe secret <secret> -n <ns> and compare the keys with the Dataset's encryptOptions", "Move metaurl from options to encryptOptions"},
+		Related:     []string{"DATASET_NOT_BOUND", "POD_CRASHLOOP_BACKOFF"},
+	}
+}
+
+func (r *JuiceFSMetaURLInvalidRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	var hints []types.FailureHint
+	for _, m := range mountsWithScheme(g, "juicefs") {
+		o, ok := encryptOption(m, "metaurl")
+		if !ok {
+			o, ok = encryptOption(m, "token")
+		}
+		switch {
+		case ok:
+			if problem, bad := secretRefProblem(g, o); bad {
+				hints = append(hints, types.FailureHint{
+					ID:         r.ID(),
+					Severity:   types.SeverityCritical,
+					Component:  "Dataset",
+					Evidence:   secretEvidence(g, o, fmt.Sprintf("Mount %s: %s", mountName(m), problem)),
+					Suggestion: fmt.Sprintf("Create the Secret %s with key %s holding the metadata engine URL in the Dataset's namespace, or fix valueFrom.secretKeyRef of the %s encrypt option.", o.SecretName, o.SecretKey, o.Name),
+				})
+			}
+		case hasOptionKey(m, "metaurl"):
+			hints = append(hints, types.FailureHint{
+				ID:         r.ID(),
+				Severity:   types.SeverityWarning,
+				Component:  "Dataset",
+				Evidence:   factEvidence("Dataset", g.Dataset.Name, "Mount {mount}: metaurl is a plain option", fact("mount", mountName(m))),
+				Suggestion: "The metadata engine URL usually carries a password and is visible to everyone who can read the Dataset. Store it in a Secret and reference it from encryptOptions.",
+			})
+		default:
+			hints = append(hints, types.FailureHint{
+				ID:         r.ID(),
+				Severity:   types.SeverityCritical,
+				Component:  "Dataset",
+				Evidence:   factEvidence("Dataset", g.Dataset.Name, "Mount {mount}: no metaurl or token encrypt option", fact("mount", mountName(m))),
+				Suggestion: "Add an encrypt option named metaurl reading the metadata engine URL from a Secret: encryptOptions: [{name: metaurl, valueFrom: {secretKeyRef: {name: <secret>, key: metaurl}}}].",
+			})
+		}
+	}
+	return hints
+}
+
+// juicefsStorageCredentialKeys are the encrypt options JuiceFS reads the object
+// storage's AccessKey pair from, when the volume is formatted with a bucket.
+var juicefsStorageCredentialKeys = []string{"access-key", "secret-key"}
+
+// JUICEFS_STORAGE_CREDENTIALS_INVALID
+// JuiceFS stores the data of a volume in an object storage bucket; the community
+// edition formats the volume with the bucket's AccessKey pair, read from a Secret
+// through the access-key and secret-key encrypt options.
+type JuiceFSStorageCredentialsInvalidRule struct{}
+
+func (r *JuiceFSStorageCredentialsInvalidRule) ID() string {
+	return "JUICEFS_STORAGE_CREDENTIALS_INVALID"
+}
+
+func (r *JuiceFSStorageCredentialsInvalidRule) RuntimeTypes() []string {
+	return []string{"JuiceFSRuntime"}
+}
+
+func (r *JuiceFSStorageCredentialsInvalidRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "JuiceFS object storage credentials invalid",
+		Description: "JuiceFSRuntime only: a juicefs:// mount's access-key or secret-key Secret or key is missing or only one of them is set (Critical), or it is a plain option (Warning).",
+		Severity:    "Critical/Warning",
+		Component:   "Dataset",
+		Checks:      "The access-key and secret-key options and encryptOptions of each juicefs:// mount of the Dataset, and the Secret keys they read from. Only key names are read, never values.",
+		Causes:      []string{"The Secret was created in another namespace than the Dataset", "access-key set without secret-key", "The AccessKey given in options"},
+		Remediation: []string{"kubectl describe secret <secret> -n <ns> and compare the keys with the Dataset's encryptOptions", "Set both access-key and secret-key through encryptOptions"},
+		Related:     []string{"JUICEFS_METAURL_INVALID", "POD_CRASHLOOP_BACKOFF"},
+	}
+}
+
+func (r *JuiceFSStorageCredentialsInvalidRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	var hints []types.FailureHint
+	for _, m := range mountsWithScheme(g, "juicefs") {
+		hints = append(hints, credentialHints(g, r.ID(), m, juicefsStorageCredentialKeys)...)
+	}
+	return hints
+}
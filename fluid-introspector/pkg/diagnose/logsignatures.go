@@ -0,0 +1,249 @@
+package diagnose
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+)
+
+// LogRule is implemented by rules that read the sampled logs of runtime pods, keyed
+// by pod name as in DiagnosticContext.PodLogs. The engine calls EvaluateLogs when the
+// run was given logs (see WithPodLogs); otherwise Evaluate, which sees no logs.
+type LogRule interface {
+	Rule
+	EvaluateLogs(g *types.ResourceGraph, logs map[string]string) []types.FailureHint
+}
+
+// LogSignature is a known failure message of one or more runtimes. Rule files declare
+// them next to rules:
+//
+//	signatures:
+//	  - id: LOG_UFS_TIMEOUT
+//	    severity: Warning
+//	    runtimes: [AlluxioRuntime]
+//	    components: [master]
+//	    pattern: "SocketTimeoutException: .*(oss|s3)"
+//	    suggestion: The master times out reaching the under storage; check the endpoint and network policies.
+type LogSignature struct {
+	ID          string              `json:"id"`
+	Title       string              `json:"title,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Severity    types.SeverityLevel `json:"severity"`
+	Runtimes    []string            `json:"runtimes,omitempty"`   // Runtime types, e.g. JuiceFSRuntime; empty matches every runtime
+	Components  []string            `json:"components,omitempty"` // master, worker or fuse; empty matches every component
+	Pattern     string              `json:"pattern"`              // RE2 expression matched against each log line
+	Suggestion  string              `json:"suggestion"`
+}
+
+// maxLogEvidence bounds the matched lines kept per pod, and maxLogLineLen their length.
+const (
+	maxLogEvidence = 5
+	maxLogLineLen  = 300
+)
+
+// LogSignatureRule reports every runtime pod whose sampled logs match a LogSignature.
+type LogSignatureRule struct {
+	sig     LogSignature
+	pattern *regexp.Regexp
+}
+
+// NewLogSignatureRule validates a signature and compiles its pattern.
+func NewLogSignatureRule(sig LogSignature) (*LogSignatureRule, error) {
+	if !ruleIDPattern.MatchString(sig.ID) {
+		return nil, fmt.Errorf("signature id %q must be UPPER_SNAKE_CASE", sig.ID)
+	}
+	if severityRank(sig.Severity) == 0 {
+		return nil, fmt.Errorf("signature %s: severity %q must be one of Critical, Warning, Info", sig.ID, sig.Severity)
+	}
+	if strings.TrimSpace(sig.Pattern) == "" {
+		return nil, fmt.Errorf("signature %s: pattern is required", sig.ID)
+	}
+	for _, c := range sig.Components {
+		if c != "master" && c != "worker" && c != "fuse" {
+			return nil, fmt.Errorf("signature %s: component %q must be one of master, worker, fuse", sig.ID, c)
+		}
+	}
+	pattern, err := regexp.Compile(sig.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("signature %s: invalid pattern: %w", sig.ID, err)
+	}
+	return &LogSignatureRule{sig: sig, pattern: pattern}, nil
+}
+
+func (r *LogSignatureRule) ID() string { return r.sig.ID }
+
+func (r *LogSignatureRule) RuntimeTypes() []string { return r.sig.Runtimes }
+
+func (r *LogSignatureRule) Metadata() Metadata {
+	component := "Runtime"
+	if len(r.sig.Components) == 1 {
+		component = map[string]string{"master": "Runtime/Master", "worker": "Runtime/Worker", "fuse": "Runtime/Fuse"}[r.sig.Components[0]]
+	}
+	pods := "runtime"
+	if len(r.sig.Components) > 0 {
+		pods = strings.Join(r.sig.Components, " and ")
+	}
+	m := Metadata{
+		Title:       r.sig.Title,
+		Description: r.sig.Description,
+		Severity:    string(r.sig.Severity),
+		Component:   component,
+		Checks:      fmt.Sprintf("Each line of the sampled logs of the %s pods against %s. Not reported when logs were not sampled (--logs).", pods, r.sig.Pattern),
+	}
+	if r.sig.Suggestion != "" {
+		m.Remediation = []string{r.sig.Suggestion}
+	}
+	return m
+}
+
+// Inputs counts the pods whose logs the signature is matched against.
+func (r *LogSignatureRule) Inputs(g *types.ResourceGraph) []types.Fact {
+	pods := 0
+	forEachPod(g, func(rc runtimeComponent, _ types.PodInfo) {
+		if r.watches(componentField(rc.component)) {
+			pods++
+		}
+	})
+	return []types.Fact{{Key: "pods", Value: strconv.Itoa(pods)}}
+}
+
+// Evaluate reports nothing: without sampled logs there is nothing to match.
+func (r *LogSignatureRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	return nil
+}
+
+func (r *LogSignatureRule) EvaluateLogs(g *types.ResourceGraph, logs map[string]string) []types.FailureHint {
+	var hints []types.FailureHint
+	forEachPod(g, func(rc runtimeComponent, p types.PodInfo) {
+		if !r.watches(componentField(rc.component)) {
+			return
+		}
+		matched, total := r.match(logs[p.Name])
+		if total == 0 {
+			return
+		}
+		ev := factEvidence("Pod", p.Name, r.detail(total), fact("lines", total))
+		ev.Logs = matched
+		hints = append(hints, types.FailureHint{
+			ID:         r.sig.ID,
+			Severity:   r.sig.Severity,
+			Component:  rc.component,
+			Evidence:   ev,
+			Suggestion: r.sig.Suggestion,
+		})
+	})
+	return hints
+}
+
+// detail names the signature, or its pattern if untitled, and counts the matches: the
+// lines fact, see renderDetail.
+func (r *LogSignatureRule) detail(total int) string {
+	what := r.sig.Title
+	if what == "" {
+		what = "Match of " + r.sig.Pattern
+	}
+	lines := "lines"
+	if total == 1 {
+		lines = "line"
+	}
+	return fmt.Sprintf("%s in {lines} sampled log %s", what, lines)
+}
+
+func (r *LogSignatureRule) watches(component string) bool {
+	if len(r.sig.Components) == 0 {
+		return true
+	}
+	for _, c := range r.sig.Components {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns the last maxLogEvidence matching lines of a log, trimmed, and the
+// number of matching lines.
+func (r *LogSignatureRule) match(log string) ([]string, int) {
+	var matched []string
+	total := 0
+	for _, line := range strings.Split(log, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || !r.pattern.MatchString(line) {
+			continue
+		}
+		total++
+		if len(line) > maxLogLineLen {
+			line = line[:maxLogLineLen] + "..."
+		}
+		matched = append(matched, line)
+	}
+	if len(matched) > maxLogEvidence {
+		matched = matched[len(matched)-maxLogEvidence:]
+	}
+	return matched, total
+}
+
+// builtinLogSignatures are failures that runtimes report in their logs only, while
+// their pods keep running.
+var builtinLogSignatures = []LogSignature{
+	{
+		ID:          "LOG_FUSE_TRANSPORT_ENDPOINT",
+		Title:       "FUSE mount point disconnected",
+		Description: "A runtime pod logs \"Transport endpoint is not connected\": the FUSE daemon behind a mount point went away.",
+		Severity:    types.SeverityCritical,
+		Pattern:     `(?i)transport endpoint is not connected`,
+		Suggestion:  "The fuse process serving the mount point exited, usually because the fuse pod restarted or was OOM killed. Check the fuse pod's restarts, then restart the application pods on the node to remount the Dataset.",
+	},
+	{
+		ID:          "LOG_UFS_MOUNT_FAILED",
+		Title:       "Under storage mount failed",
+		Description: "AlluxioRuntime and JindoRuntime: the master logs a failed mount of the Dataset's under storage.",
+		Severity:    types.SeverityCritical,
+		Runtimes:    []string{"AlluxioRuntime", "JindoRuntime"},
+		Components:  []string{"master"},
+		Pattern:     `(?i)(failed to mount|mount failed|ufs path .* does not exist|InvalidPathException)`,
+		Suggestion:  "Check the Dataset's mountPoint (bucket, path and scheme) and the mount options, e.g. the endpoint; the master cannot read the under storage.",
+	},
+	{
+		ID:          "LOG_PERMISSION_DENIED",
+		Title:       "Permission denied",
+		Description: "A runtime pod logs permission errors from the under storage or the local file system.",
+		Severity:    types.SeverityWarning,
+		Pattern:     `(?i)(permission denied|access ?denied|403 forbidden)`,
+		Suggestion:  "Check the credentials the Dataset mounts with, and the user the runtime runs as (runAs in the Dataset) against the owner of the under storage path and the host paths of the tiered store.",
+	},
+	{
+		ID:          "LOG_KERBEROS_FAILURE",
+		Title:       "Kerberos authentication failed",
+		Description: "AlluxioRuntime and JindoRuntime: a runtime pod fails to authenticate to a Kerberized HDFS.",
+		Severity:    types.SeverityCritical,
+		Runtimes:    []string{"AlluxioRuntime", "JindoRuntime"},
+		Pattern:     `(GSSException|KrbException|Client not found in Kerberos database|Clock skew too great|Login failure for .* from keytab)`,
+		Suggestion:  "Check that the keytab and krb5.conf mounted into the runtime match the principal in the mount options, and that the nodes' clocks are in sync with the KDC.",
+	},
+	{
+		ID:          "LOG_METADATA_ENGINE_UNREACHABLE",
+		Title:       "JuiceFS metadata engine unreachable",
+		Description: "JuiceFSRuntime only: a worker or fuse pod cannot connect to the metadata engine (Redis, MySQL, TiKV, ...).",
+		Severity:    types.SeverityCritical,
+		Runtimes:    []string{"JuiceFSRuntime"},
+		Pattern:     `(?i)(load setting: .*|meta(data)? .*)(connection refused|i/o timeout|no such host|no route to host)`,
+		Suggestion:  "Check that the host in the metaurl resolves and accepts connections from the runtime's nodes, e.g. that no network policy blocks it.",
+	},
+}
+
+// builtinLogRules compiles the built-in signatures; they are fixed, so errors are bugs.
+func builtinLogRules() []Rule {
+	rules := make([]Rule, 0, len(builtinLogSignatures))
+	for _, sig := range builtinLogSignatures {
+		r, err := NewLogSignatureRule(sig)
+		if err != nil {
+			panic(err)
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
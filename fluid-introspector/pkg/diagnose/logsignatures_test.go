@@ -0,0 +1,128 @@
+package diagnose_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/diagnose"
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// logGraph is a healthy graph of the given runtime type with one master, worker and fuse pod.
+func logGraph(runtimeType string) *types.ResourceGraph {
+	pod := func(name string) []types.PodInfo {
+		return []types.PodInfo{{Name: name, Status: "Running", Phase: "Running", Ready: true}}
+	}
+	g := packGraph(runtimeType)
+	g.Runtime.Master = &types.ComponentInfo{Name: "demo-data-master", Replicas: 1, Ready: 1, Pods: pod("demo-data-master-0")}
+	g.Runtime.Worker.Pods = pod("demo-data-worker-0")
+	g.Runtime.Fuse.Pods = pod("demo-data-fuse-abcde")
+	return g
+}
+
+func TestLogSignatures_MatchSampledLogs(t *testing.T) {
+	g := logGraph("AlluxioRuntime")
+	logs := map[string]string{
+		"demo-data-master-0":   "INFO Starting master\nERROR Failed to mount oss://bucket/train at /: Access Denied\n",
+		"demo-data-worker-0":   "INFO Worker started\n",
+		"demo-data-fuse-abcde": "ERROR stat /mnt/alluxio-fuse: Transport endpoint is not connected\n",
+	}
+
+	// Without logs, log rules report nothing.
+	assert.True(t, diagnose.Diagnose(g).IsHealthy)
+
+	result, err := diagnose.Run(g, diagnose.WithPodLogs(logs))
+	require.NoError(t, err)
+	var got []string
+	for _, h := range result.FailureHints {
+		got = append(got, h.ID+" "+h.Component+" "+h.Evidence.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		"LOG_FUSE_TRANSPORT_ENDPOINT Runtime/Fuse demo-data-fuse-abcde",
+		"LOG_UFS_MOUNT_FAILED Runtime/Master demo-data-master-0",
+		"LOG_PERMISSION_DENIED Runtime/Master demo-data-master-0",
+	}, got)
+
+	hints := findings(result, "LOG_UFS_MOUNT_FAILED")
+	require.Len(t, hints, 1)
+	assert.Equal(t, "Under storage mount failed in 1 sampled log line", hints[0].Evidence.Detail)
+	assert.Equal(t, []string{"ERROR Failed to mount oss://bucket/train at /: Access Denied"}, hints[0].Evidence.Logs)
+	assert.Equal(t, "kubectl describe pod demo-data-master-0 -n default", hints[0].Remediation[0].Command)
+
+	// The Alluxio mount signature does not apply to JuiceFS.
+	g.Runtime.Type = "JuiceFSRuntime"
+	result, err = diagnose.Run(g, diagnose.WithPodLogs(logs))
+	require.NoError(t, err)
+	assert.Empty(t, findings(result, "LOG_UFS_MOUNT_FAILED"))
+	assert.Len(t, findings(result, "LOG_PERMISSION_DENIED"), 1)
+}
+
+func TestLogSignatures_EvidenceIsBounded(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 8; i++ {
+		fmt.Fprintf(&b, "line %d: Permission denied\n", i)
+	}
+	fmt.Fprintf(&b, "%s Permission denied\n", strings.Repeat("x", 400))
+
+	result, err := diagnose.Run(logGraph("JuiceFSRuntime"), diagnose.WithPodLogs(map[string]string{"demo-data-worker-0": b.String()}))
+	require.NoError(t, err)
+	hints := findings(result, "LOG_PERMISSION_DENIED")
+	require.Len(t, hints, 1)
+	assert.Equal(t, "Permission denied in 9 sampled log lines", hints[0].Evidence.Detail)
+	require.Len(t, hints[0].Evidence.Logs, 5)
+	assert.Equal(t, "line 4: Permission denied", hints[0].Evidence.Logs[0], "keeps the latest lines")
+	assert.Len(t, hints[0].Evidence.Logs[4], 303)
+}
+
+func TestLogSignatures_ProfileOverride(t *testing.T) {
+	set, err := diagnose.DefaultRegistry.RuleSet("LOG_PERMISSION_DENIED")
+	require.NoError(t, err)
+	profile := &diagnose.Profile{Name: "strict", Rules: map[string]diagnose.RuleOverride{"LOG_PERMISSION_DENIED": {Severity: types.SeverityCritical}}}
+
+	logs := map[string]string{"demo-data-fuse-abcde": "open /cache: permission denied"}
+	result, err := diagnose.Run(logGraph("AlluxioRuntime"), diagnose.WithRuleSet(set), diagnose.WithProfile(profile), diagnose.WithPodLogs(logs))
+	require.NoError(t, err)
+	require.Len(t, result.FailureHints, 1)
+	assert.Equal(t, types.SeverityCritical, result.FailureHints[0].Severity)
+}
+
+func TestLogSignatures_LoadFromRuleFile(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "signatures.yaml", `
+signatures:
+  - id: LOG_CACHE_DISK_FULL
+    title: Cache disk full
+    severity: Warning
+    components: [worker]
+    pattern: "(?i)no space left on device"
+    suggestion: Lower the tiered store quota.
+`)
+	loaded, err := diagnose.LoadRules(path)
+	require.NoError(t, err)
+	require.Len(t, loaded, 1)
+	assert.Equal(t, "Runtime/Worker", diagnose.Describe(loaded[0]).Component)
+
+	set, err := diagnose.NewRuleSet(loaded...)
+	require.NoError(t, err)
+	logs := map[string]string{
+		"demo-data-worker-0":   "write block: No space left on device",
+		"demo-data-fuse-abcde": "write block: No space left on device",
+	}
+	result, err := diagnose.Run(logGraph("JindoRuntime"), diagnose.WithRuleSet(set), diagnose.WithPodLogs(logs))
+	require.NoError(t, err)
+	require.Len(t, result.FailureHints, 1, "only worker logs are scanned")
+	assert.Equal(t, "demo-data-worker-0", result.FailureHints[0].Evidence.Name)
+	assert.Equal(t, "Lower the tiered store quota.", result.FailureHints[0].Suggestion)
+
+	for _, bad := range []string{
+		"signatures:\n  - {id: LOG_X, severity: Warning, pattern: '('}",
+		"signatures:\n  - {id: LOG_X, severity: Warning, pattern: x, components: [node]}",
+		"signatures:\n  - {id: log-x, severity: Warning, pattern: x}",
+		"signatures:\n  - {id: LOG_X, severity: Fatal, pattern: x}",
+	} {
+		_, err := diagnose.LoadRules(writeFile(t, t.TempDir(), "bad.yaml", bad))
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,93 @@
+package diagnose
+
+import (
+	"context"
+	"time"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+)
+
+// Option configures a diagnosis run.
+type Option func(*options)
+
+type options struct {
+	ctx      context.Context
+	now      func() time.Time
+	rules    RuleSet
+	hasRules bool
+	profile  *Profile
+	fleet    []*types.ResourceGraph
+	logs     map[string]string
+	trace    bool
+}
+
+// WithContext lets ctx cancel the run between rules. The default never cancels.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) { o.ctx = ctx }
+}
+
+// WithClock sets the clock that stamps the result and decides silence expiry for
+// graphs without a snapshot time. The default is time.Now; with a fixed clock,
+// identical graphs yield identical results.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) { o.now = now }
+}
+
+// WithRuleSet sets the rules to evaluate, in order. The default is the rules of the
+// DefaultRegistry.
+func WithRuleSet(rules RuleSet) Option {
+	return func(o *options) { o.rules, o.hasRules = rules, true }
+}
+
+// WithProfile applies the profile to the rule set, overrides the severities of the
+// findings it configures and records its name in the result.
+func WithProfile(profile *Profile) Option {
+	return func(o *options) { o.profile = profile }
+}
+
+// WithPodLogs gives log rules the sampled logs of the runtime pods, keyed by pod
+// name, e.g. DiagnosticContext.PodLogs. Without it, log rules report nothing.
+func WithPodLogs(logs map[string]string) Option {
+	return func(o *options) { o.logs = logs }
+}
+
+// WithTrace explains every rule of the run in DiagnosticResult.Trace: the graph
+// values it read, and whether it fired, passed or was skipped, and why. Rules a
+// profile disabled are traced as skipped.
+func WithTrace() Option {
+	return func(o *options) { o.trace = true }
+}
+
+// withFleet lets cluster rules compare the graph to the other graphs of the fleet.
+func withFleet(fleet []*types.ResourceGraph) Option {
+	return func(o *options) { o.fleet = fleet }
+}
+
+// Run diagnoses the graph as configured by opts. It fails if the profile does not
+// apply to the rule set or the context is done before all rules ran. A nil graph
+// yields a nil result.
+func Run(graph *types.ResourceGraph, opts ...Option) (*types.DiagnosticResult, error) {
+	o := options{ctx: context.Background(), now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if !o.hasRules {
+		o.rules, _ = DefaultRegistry.RuleSet() // Cannot fail without IDs
+	}
+
+	set, err := o.profile.Apply(o.rules)
+	if err != nil {
+		return nil, err
+	}
+	result, err := diagnoseGraph(graph, set, &o)
+	if err != nil {
+		return nil, err
+	}
+	if result != nil && o.profile != nil {
+		result.Profile = o.profile.Name
+		if o.trace {
+			result.Trace = traceDisabled(result.Trace, o.rules, o.profile)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,178 @@
+package diagnose_test
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/diagnose"
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var goldenNow = time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+// goldenGraph exercises every part of the result: escalation, correlation, silences,
+// per-pod findings and maps, whose key order must not leak into the output.
+func goldenGraph() *types.ResourceGraph {
+	return &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Name: "demo-data", Namespace: "default", Status: "NotBound", Phase: "Pending",
+			Labels:            map[string]string{"team": "vision", "env": "prod", "app": "train"},
+			CreationTimestamp: goldenNow.Add(-time.Hour),
+			Silences:          []types.Silence{{RuleID: "FUSE_MISSING", Reason: "fuse pool migration"}},
+		},
+		Runtime: &types.RuntimeInfo{
+			Name: "demo-data",
+			Type: "AlluxioRuntime",
+			Master: &types.ComponentInfo{Name: "demo-data-master", Replicas: 1, Ready: 0, CreationTimestamp: goldenNow.Add(-time.Hour),
+				Pods: []types.PodInfo{{Name: "demo-data-master-0", Status: "CrashLoopBackOff", Node: "node-1", Restarts: 7,
+					NodeSelector: map[string]string{"zone": "a", "pool": "cache", "arch": "amd64"},
+					Requests:     corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi"), corev1.ResourceCPU: resource.MustParse("2")},
+					Containers: []types.ContainerInfo{{Name: "alluxio-master", Image: "alluxio/alluxio:2.9.2", RestartCount: 7,
+						State: &corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}},
+				}},
+			},
+			Worker: &types.ComponentInfo{Name: "demo-data-worker", Replicas: 2, Ready: 1, CreationTimestamp: goldenNow.Add(-time.Hour)},
+			Fuse:   &types.ComponentInfo{Name: "demo-data-fuse", Replicas: 2, Ready: 0, CreationTimestamp: goldenNow.Add(-time.Hour)},
+		},
+		Infrastructure: &types.InfrastructureInfo{
+			PVC: &types.PVCInfo{Name: "demo-data", Status: "Pending", CreationTimestamp: goldenNow.Add(-time.Minute)},
+		},
+		ObservedAt: goldenNow,
+	}
+}
+
+func TestRun_GoldenJSON(t *testing.T) {
+	render := func() []byte {
+		result, err := diagnose.Run(goldenGraph(), diagnose.WithClock(func() time.Time { return goldenNow }))
+		require.NoError(t, err)
+		out, err := json.MarshalIndent(result, "", "  ")
+		require.NoError(t, err)
+		return append(out, '\n')
+	}
+
+	first := render()
+	for i := 0; i < 10; i++ {
+		require.Equal(t, string(first), string(render()), "identical inputs must render identical JSON")
+	}
+
+	golden := filepath.Join("testdata", "golden_result.json")
+	if *update {
+		require.NoError(t, os.MkdirAll("testdata", 0o755))
+		require.NoError(t, os.WriteFile(golden, first, 0o644))
+	}
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err, "run with -update to create the golden file")
+	assert.Equal(t, string(want), string(first))
+}
+
+func TestRun_Options(t *testing.T) {
+	graph := &types.ResourceGraph{Dataset: &types.DatasetInfo{Name: "demo-data", Status: "Bound"}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := diagnose.Run(graph, diagnose.WithContext(ctx))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	set, err := diagnose.DefaultRegistry.RuleSet("RUNTIME_MISSING")
+	require.NoError(t, err)
+	profile := &diagnose.Profile{Name: "strict", Rules: map[string]diagnose.RuleOverride{"RUNTIME_MISSING": {Severity: types.SeverityWarning}}}
+	result, err := diagnose.Run(graph, diagnose.WithRuleSet(set), diagnose.WithProfile(profile), diagnose.WithClock(func() time.Time { return goldenNow }))
+	require.NoError(t, err)
+	assert.Equal(t, goldenNow, result.Timestamp)
+	assert.Equal(t, "strict", result.Profile)
+	require.Len(t, result.FailureHints, 1)
+	assert.Equal(t, types.SeverityWarning, result.FailureHints[0].Severity)
+
+	// An explicitly empty rule set runs no rules.
+	result, err = diagnose.Run(graph, diagnose.WithRuleSet(diagnose.RuleSet{}))
+	require.NoError(t, err)
+	assert.True(t, result.IsHealthy)
+}
+
+func TestRun_Trace(t *testing.T) {
+	// Untraced runs carry no trace, so the golden output is unchanged.
+	result, err := diagnose.Run(goldenGraph())
+	require.NoError(t, err)
+	assert.Nil(t, result.Trace)
+
+	profile := &diagnose.Profile{Name: "quiet", Rules: map[string]diagnose.RuleOverride{"OOM_KILLED": {Disabled: true}}}
+	result, err = diagnose.Run(goldenGraph(), diagnose.WithProfile(profile), diagnose.WithTrace())
+	require.NoError(t, err)
+
+	all, err := diagnose.DefaultRegistry.RuleSet()
+	require.NoError(t, err)
+	require.Len(t, result.Trace, len(all), "every rule is traced, disabled ones included")
+	trace := make(map[string]types.RuleTrace)
+	for i, tr := range result.Trace {
+		assert.Equal(t, all[i].ID(), tr.RuleID, "in rule set order")
+		trace[tr.RuleID] = tr
+	}
+
+	bound := trace["DATASET_NOT_BOUND"]
+	assert.Equal(t, types.TraceFired, bound.Outcome)
+	assert.Equal(t, 1, bound.Findings)
+	assert.Contains(t, bound.Reason, "Dataset/demo-data: ")
+	assert.Contains(t, bound.Inputs, types.Fact{Key: "status", Value: "NotBound"})
+
+	fuse := trace["FUSE_MISSING"]
+	assert.Equal(t, types.TraceFired, fuse.Outcome)
+	assert.Equal(t, 1, fuse.Silenced)
+	assert.Equal(t, []types.Fact{{Key: "readyReplicas", Value: "0"}, {Key: "replicas", Value: "2"}, {Key: "pods", Value: "0"}, {Key: "restarts", Value: "0"}}, fuse.Inputs)
+
+	evicted := trace["POD_EVICTED"]
+	assert.Equal(t, types.TracePassed, evicted.Outcome)
+	assert.Equal(t, "Checked: Runtime pods whose status reason is Evicted.", evicted.Reason)
+
+	assert.Equal(t, types.RuleTrace{RuleID: "OOM_KILLED", Outcome: types.TraceSkipped, Reason: "Disabled by profile quiet"}, trace["OOM_KILLED"])
+	assert.Equal(t, types.RuleTrace{
+		RuleID:  "THIN_PROFILE_MISSING",
+		Outcome: types.TraceSkipped,
+		Reason:  "Applies to ThinRuntime only; the runtime is AlluxioRuntime",
+		Inputs:  []types.Fact{{Key: "runtime", Value: "AlluxioRuntime"}},
+	}, trace["THIN_PROFILE_MISSING"])
+	assert.Equal(t, types.TraceSkipped, trace["LOG_PERMISSION_DENIED"].Outcome)
+	assert.Equal(t, "No pod logs were sampled (--logs)", trace["LOG_PERMISSION_DENIED"].Reason)
+	assert.Contains(t, trace["TIEREDSTORE_MEM_EXCEEDS_LIMIT"].Inputs, types.Fact{Key: "workerMemoryLimit", Value: "unknown"})
+
+	// With logs, log rules run.
+	result, err = diagnose.Run(goldenGraph(), diagnose.WithTrace(), diagnose.WithPodLogs(map[string]string{}))
+	require.NoError(t, err)
+	for _, tr := range result.Trace {
+		if tr.RuleID == "LOG_PERMISSION_DENIED" {
+			assert.Equal(t, types.TracePassed, tr.Outcome)
+		}
+	}
+}
+
+func TestRun_TraceErrorsAndInvalidGraphs(t *testing.T) {
+	set, err := diagnose.NewRuleSet(&panicRule{}, &labelRule{})
+	require.NoError(t, err)
+
+	graph := &types.ResourceGraph{Dataset: &types.DatasetInfo{Name: "demo-data", Status: "Bound"}}
+	result, err := diagnose.Run(graph, diagnose.WithRuleSet(set), diagnose.WithTrace())
+	require.NoError(t, err)
+	require.Len(t, result.Trace, 2)
+	assert.Equal(t, types.TraceError, result.Trace[0].Outcome)
+	assert.Contains(t, result.Trace[0].Reason, "Rule panicked")
+	assert.Equal(t, types.TraceFired, result.Trace[1].Outcome)
+	assert.Equal(t, "Dataset", result.Trace[1].Reason, "findings without evidence name their component")
+
+	result, err = diagnose.Run(&types.ResourceGraph{}, diagnose.WithRuleSet(set), diagnose.WithTrace())
+	require.NoError(t, err)
+	require.Len(t, result.Trace, 2)
+	for _, tr := range result.Trace {
+		assert.Equal(t, types.TraceSkipped, tr.Outcome)
+		assert.Equal(t, "The graph is invalid: Dataset is missing from graph", tr.Reason)
+		assert.Empty(t, tr.Inputs)
+	}
+}
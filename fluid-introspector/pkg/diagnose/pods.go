@@ -0,0 +1,311 @@
+package diagnose
+
+import (
+	"fmt"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Pod failure rules classify why a master, worker or fuse pod is not running.
+// They complement the ready-count rules, which only say that it is not.
+// Master pod failures are Critical, worker and fuse pod failures Warning,
+// matching MASTER_NOT_READY, WORKER_PARTIALLY_READY and FUSE_MISSING.
+
+// POD_CRASHLOOP_BACKOFF
+type PodCrashLoopBackOffRule struct{}
+
+func (r *PodCrashLoopBackOffRule) ID() string { return "POD_CRASHLOOP_BACKOFF" }
+
+func (r *PodCrashLoopBackOffRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Container in CrashLoopBackOff",
+		Description: "A master (Critical), worker or fuse (Warning) container is in CrashLoopBackOff. Reports the last exit reason.",
+		Severity:    "Critical/Warning",
+		Component:   "Runtime pods",
+		Checks:      "The waiting state of every app container of the runtime pods.",
+		Causes:      []string{"The process exits on a configuration or UFS error", "The container is OOMKilled on every start"},
+		Remediation: []string{"kubectl logs <pod> -c <container> --previous -n <ns>"},
+		Related:     []string{"OOM_KILLED", "MASTER_NOT_READY", "WORKER_PARTIALLY_READY"},
+	}
+}
+
+// Containers killed for exceeding their memory limit crash-loop as a consequence.
+func (r *PodCrashLoopBackOffRule) CausedBy() []string { return []string{"OOM_KILLED"} }
+
+func (r *PodCrashLoopBackOffRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	var hints []types.FailureHint
+	forEachContainer(g, func(rc runtimeComponent, p types.PodInfo, c types.ContainerInfo) {
+		// Crash-looping init containers are reported by POD_INIT_FAILED.
+		if c.Init || waitingReason(c) != "CrashLoopBackOff" {
+			return
+		}
+		format := "Container {container}: CrashLoopBackOff, Restarts: {restarts}"
+		facts := []types.Fact{fact("container", c.Name), fact("restarts", c.RestartCount)}
+		if t := terminated(c.LastState); t != nil {
+			format += ", Last exit: {lastExitReason} ({lastExitCode})"
+			facts = append(facts, fact("lastExitReason", t.Reason), fact("lastExitCode", t.ExitCode))
+		}
+		hint := podHint(r.ID(), rc, p, format,
+			fmt.Sprintf("Inspect the previous run: kubectl logs %s -c %s --previous%s", p.Name, c.Name, namespaceFlag(g)), facts...)
+		hint.Remediation = containerRemediation(g, p, c)
+		hints = append(hints, hint)
+	})
+	return hints
+}
+
+// POD_IMAGE_PULL_FAILED
+type PodImagePullFailedRule struct{}
+
+func (r *PodImagePullFailedRule) ID() string { return "POD_IMAGE_PULL_FAILED" }
+
+func (r *PodImagePullFailedRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Image pull failed",
+		Description: "A runtime container image cannot be pulled (ImagePullBackOff, ErrImagePull, InvalidImageName).",
+		Severity:    "Critical/Warning",
+		Component:   "Runtime pods",
+		Checks:      "The waiting state of every runtime container, init containers included.",
+		Causes:      []string{"A wrong image or tag in the Runtime spec", "A private registry without imagePullSecrets", "The node cannot reach the registry"},
+		Remediation: []string{"kubectl describe pod <pod> -n <ns> and read the Events", "Check the image and tag in the Runtime spec and its imagePullSecrets"},
+		Related:     []string{"IMAGE_VERSION_SKEW", "RUNTIME_WORKLOAD_DRIFT"},
+	}
+}
+
+func (r *PodImagePullFailedRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	var hints []types.FailureHint
+	forEachContainer(g, func(rc runtimeComponent, p types.PodInfo, c types.ContainerInfo) {
+		reason := waitingReason(c)
+		switch reason {
+		case "ImagePullBackOff", "ErrImagePull", "InvalidImageName", "ErrImageNeverPull":
+		default:
+			return
+		}
+		hints = append(hints, podHint(r.ID(), rc, p, "Container {container}: {reason}, Image: {image}",
+			fmt.Sprintf("Check that the image exists and the registry is reachable from node %s. Fix the image in spec.%s of the %s, or add imagePullSecrets for a private registry.",
+				orUnknown(p.Node), componentField(rc.component), runtimeKind(g)),
+			fact("container", c.Name), fact("reason", reason), fact("image", c.Image)))
+	})
+	return hints
+}
+
+// POD_CONFIG_ERROR
+type PodConfigErrorRule struct{}
+
+func (r *PodConfigErrorRule) ID() string { return "POD_CONFIG_ERROR" }
+
+func (r *PodConfigErrorRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Container cannot be created",
+		Description: "A container cannot be created, typically because a referenced ConfigMap or Secret is missing.",
+		Severity:    "Critical/Warning",
+		Component:   "Runtime pods",
+		Checks:      "CreateContainerConfigError and CreateContainerError waiting states.",
+		Causes:      []string{"A ConfigMap or Secret referenced by the pod does not exist", "A key missing from a referenced ConfigMap or Secret"},
+		Remediation: []string{"kubectl describe pod <pod> -n <ns>", "kubectl get configmap,secret -n <ns>"},
+	}
+}
+
+func (r *PodConfigErrorRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	var hints []types.FailureHint
+	forEachContainer(g, func(rc runtimeComponent, p types.PodInfo, c types.ContainerInfo) {
+		reason := waitingReason(c)
+		if reason != "CreateContainerConfigError" && reason != "CreateContainerError" {
+			return
+		}
+		format := "Container {container}: {reason}"
+		facts := []types.Fact{fact("container", c.Name), fact("reason", reason)}
+		if msg := c.State.Waiting.Message; msg != "" {
+			format += ": {message}"
+			facts = append(facts, fact("message", msg))
+		}
+		hints = append(hints, podHint(r.ID(), rc, p, format,
+			"A referenced ConfigMap, Secret or key is missing, or the container spec is invalid. Create the referenced object or fix the reference in the Runtime or Dataset.", facts...))
+	})
+	return hints
+}
+
+// POD_INIT_FAILED
+type PodInitFailedRule struct{}
+
+func (r *PodInitFailedRule) ID() string { return "POD_INIT_FAILED" }
+
+func (r *PodInitFailedRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Init container failed",
+		Description: "An init container exited non-zero or is crash-looping.",
+		Severity:    "Critical/Warning",
+		Component:   "Runtime pods",
+		Checks:      "The state of the init containers of every runtime pod.",
+		Causes:      []string{"The init container cannot prepare cache directories or permissions", "A dependency the init container waits for is unavailable"},
+		Remediation: []string{"kubectl logs <pod> -c <init container> -n <ns>"},
+	}
+}
+
+func (r *PodInitFailedRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	var hints []types.FailureHint
+	forEachContainer(g, func(rc runtimeComponent, p types.PodInfo, c types.ContainerInfo) {
+		if !c.Init {
+			return
+		}
+		var format string
+		facts := []types.Fact{fact("container", c.Name)}
+		if t := terminated(c.State); t != nil && t.ExitCode != 0 {
+			format = "Init container {container}: exited {exitCode} ({reason})"
+			facts = append(facts, fact("exitCode", t.ExitCode), fact("reason", t.Reason))
+		} else if waitingReason(c) == "CrashLoopBackOff" {
+			format = "Init container {container}: CrashLoopBackOff, Restarts: {restarts}"
+			facts = append(facts, fact("restarts", c.RestartCount))
+		} else {
+			return
+		}
+		hint := podHint(r.ID(), rc, p, format,
+			fmt.Sprintf("The pod cannot start until its init containers succeed: kubectl logs %s -c %s%s", p.Name, c.Name, namespaceFlag(g)), facts...)
+		hint.Remediation = containerRemediation(g, p, c)
+		hints = append(hints, hint)
+	})
+	return hints
+}
+
+// POD_EVICTED
+type PodEvictedRule struct{}
+
+func (r *PodEvictedRule) ID() string { return "POD_EVICTED" }
+
+func (r *PodEvictedRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Pod evicted",
+		Description: "A runtime pod was evicted under node memory or disk pressure.",
+		Severity:    "Critical/Warning",
+		Component:   "Runtime pods",
+		Checks:      "Runtime pods whose status reason is Evicted.",
+		Causes:      []string{"Node disk pressure, e.g. from disk cache levels", "Node memory pressure"},
+		Remediation: []string{"kubectl describe node <node> and check its conditions", "Delete the evicted pod so the controller recreates it"},
+		Related:     []string{"TIEREDSTORE_DISK_PRESSURE"},
+	}
+}
+
+// Disk cache levels filling a node trigger the kubelet's eviction.
+func (r *PodEvictedRule) CausedBy() []string { return []string{"TIEREDSTORE_DISK_PRESSURE"} }
+
+func (r *PodEvictedRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	var hints []types.FailureHint
+	forEachPod(g, func(rc runtimeComponent, p types.PodInfo) {
+		if p.Reason != "Evicted" {
+			return
+		}
+		format := "Evicted"
+		var facts []types.Fact
+		if p.Message != "" {
+			format += ": {message}"
+			facts = append(facts, fact("message", p.Message))
+		}
+		if p.Node != "" {
+			format += ", Node: {node}"
+			facts = append(facts, fact("node", p.Node))
+		}
+		hints = append(hints, podHint(r.ID(), rc, p, format,
+			"The node ran short of memory or disk. Set resource requests on the Runtime so the pod is not evicted first, and check cache directories against node disk capacity.", facts...))
+	})
+	return hints
+}
+
+// ----------------------------------------------------------------------------
+// Helpers
+// ----------------------------------------------------------------------------
+
+// forEachPod visits the pods of every runtime component in component order. Components
+// scaled down on purpose are skipped; their remaining pods are terminating.
+func forEachPod(g *types.ResourceGraph, fn func(rc runtimeComponent, p types.PodInfo)) {
+	if g.Runtime == nil {
+		return
+	}
+	for _, rc := range runtimeComponents(g.Runtime) {
+		if scaledDown(rc.info) {
+			continue
+		}
+		for _, p := range rc.info.Pods {
+			fn(rc, p)
+		}
+	}
+}
+
+// forEachContainer visits every container, init containers included, of every runtime pod.
+func forEachContainer(g *types.ResourceGraph, fn func(rc runtimeComponent, p types.PodInfo, c types.ContainerInfo)) {
+	forEachPod(g, func(rc runtimeComponent, p types.PodInfo) {
+		for _, c := range p.Containers {
+			fn(rc, p, c)
+		}
+	})
+}
+
+// podHint builds a pod-level finding with the component's default severity. Its
+// detail is rendered from the facts, see renderDetail.
+func podHint(id string, rc runtimeComponent, p types.PodInfo, format, suggestion string, facts ...types.Fact) types.FailureHint {
+	severity := types.SeverityWarning
+	if rc.component == "Runtime/Master" {
+		severity = types.SeverityCritical
+	}
+	return types.FailureHint{
+		ID:         id,
+		Severity:   severity,
+		Component:  rc.component,
+		Evidence:   factEvidence("Pod", p.Name, format, facts...),
+		Suggestion: suggestion,
+	}
+}
+
+// containerRemediation reads the failing container rather than the whole pod.
+func containerRemediation(g *types.ResourceGraph, p types.PodInfo, c types.ContainerInfo) []types.RemediationStep {
+	return []types.RemediationStep{
+		containerLogsStep(g, p.Name, c),
+		{Description: "Read the pod's events", Command: fmt.Sprintf("kubectl describe pod %s%s", p.Name, namespaceFlag(g))},
+	}
+}
+
+func waitingReason(c types.ContainerInfo) string {
+	if c.State == nil || c.State.Waiting == nil {
+		return ""
+	}
+	return c.State.Waiting.Reason
+}
+
+func terminated(s *corev1.ContainerState) *corev1.ContainerStateTerminated {
+	if s == nil {
+		return nil
+	}
+	return s.Terminated
+}
+
+// componentField maps a Component label to its section of the Runtime spec.
+func componentField(component string) string {
+	switch component {
+	case "Runtime/Master":
+		return "master"
+	case "Runtime/Worker":
+		return "worker"
+	default:
+		return "fuse"
+	}
+}
+
+func runtimeKind(g *types.ResourceGraph) string {
+	if g.Runtime == nil || g.Runtime.Type == "" {
+		return "Runtime"
+	}
+	return g.Runtime.Type
+}
+
+func namespaceFlag(g *types.ResourceGraph) string {
+	if g.Dataset.Namespace == "" {
+		return ""
+	}
+	return " -n " + g.Dataset.Namespace
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "<unknown>"
+	}
+	return s
+}
@@ -0,0 +1,200 @@
+package diagnose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Profile tunes a rule set for a class of clusters:
+//
+//	name: dev-clusters
+//	rules:
+//	  FUSE_MISSING:
+//	    severity: Info
+//	  PVC_NOT_BOUND:
+//	    disabled: true
+//	  WORKER_PARTIALLY_READY:
+//	    params:
+//	      criticalReadyRatio: 0.5
+//	      gracePeriod: 10m
+type Profile struct {
+	Name  string                  `json:"name"`
+	Rules map[string]RuleOverride `json:"rules,omitempty"` // Keyed by rule ID
+}
+
+// RuleOverride changes the behavior of a single rule.
+type RuleOverride struct {
+	Disabled bool                   `json:"disabled,omitempty"`
+	Severity types.SeverityLevel    `json:"severity,omitempty"` // Replaces the severity of the rule's findings; escalation still applies, see Escalation.Severity
+	Params   map[string]interface{} `json:"params,omitempty"`   // Passed to the rule's Configure method
+}
+
+// escalatingRule is implemented by rules that escalate the severity of their findings
+// with age. withSeverity returns a copy of the rule reporting sev as its base severity,
+// so the grace period and the critical threshold still apply to a profile's severity.
+type escalatingRule interface {
+	Rule
+	withSeverity(sev types.SeverityLevel) Rule
+}
+
+// Configurable is implemented by rules with tunable parameters. Configure returns a
+// configured copy of the rule and must not modify the receiver, since rules are shared.
+// Unknown parameters are errors.
+type Configurable interface {
+	Rule
+	Configure(params map[string]string) (Rule, error)
+}
+
+// LoadProfile reads a profile from a YAML file. If the profile has no name, the
+// file name without extension is used.
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Profile{}
+	if err := yaml.UnmarshalStrict(data, p); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if p.Name == "" {
+		p.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return p, nil
+}
+
+// Apply returns a copy of the rule set with the profile's overrides applied:
+// disabled rules are dropped, parameters are passed to Configure and severities
+// replace the base severity of escalating rules. Rule order is preserved. Severities
+// of other rules are replaced by the engine as they report findings, see WithProfile.
+// Overrides for rules that are not part
+// of the set are errors, so typos do not go unnoticed.
+func (p *Profile) Apply(set RuleSet) (RuleSet, error) {
+	if p == nil {
+		return set, nil
+	}
+
+	known := make(map[string]bool, len(set))
+	for _, r := range set {
+		known[r.ID()] = true
+	}
+	ids := make([]string, 0, len(p.Rules))
+	for id := range p.Rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !known[id] {
+			return nil, fmt.Errorf("profile %s: unknown rule %q", p.Name, id)
+		}
+		if sev := p.Rules[id].Severity; sev != "" && severityRank(sev) == 0 {
+			return nil, fmt.Errorf("profile %s: rule %s: severity %q must be one of Critical, Warning, Info", p.Name, id, sev)
+		}
+	}
+
+	var out RuleSet
+	for _, r := range set {
+		o, ok := p.Rules[r.ID()]
+		if !ok {
+			out = append(out, r)
+			continue
+		}
+		if o.Disabled {
+			continue
+		}
+
+		if len(o.Params) > 0 {
+			c, ok := r.(Configurable)
+			if !ok {
+				return nil, fmt.Errorf("profile %s: rule %s has no parameters", p.Name, r.ID())
+			}
+			params := make(map[string]string, len(o.Params))
+			for k, v := range o.Params {
+				params[k] = fmt.Sprint(v)
+			}
+			configured, err := c.Configure(params)
+			if err != nil {
+				return nil, fmt.Errorf("profile %s: rule %s: %w", p.Name, r.ID(), err)
+			}
+			r = configured
+		}
+		if e, ok := r.(escalatingRule); ok && o.Severity != "" {
+			r = e.withSeverity(o.Severity)
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// DiagnoseWithProfile applies the profile to the rule set, runs the diagnosis and
+// records the profile name in the result. A nil profile behaves like DiagnoseWithRules.
+func DiagnoseWithProfile(graph *types.ResourceGraph, rules RuleSet, profile *Profile) (*types.DiagnosticResult, error) {
+	return Run(graph, WithRuleSet(rules), WithProfile(profile))
+}
+
+// overrideSeverity replaces the severity of the findings of a rule that does not
+// escalate as the profile configures; escalating rules report the profile's severity
+// themselves, see Apply. Engine findings about the rule, e.g. its panic, keep theirs.
+func (p *Profile) overrideSeverity(rule Rule, hints []types.FailureHint) {
+	if _, ok := rule.(escalatingRule); ok || p == nil || p.Rules[rule.ID()].Severity == "" {
+		return
+	}
+	for i := range hints {
+		if hints[i].ID == rule.ID() {
+			hints[i].Severity = p.Rules[rule.ID()].Severity
+		}
+	}
+}
+
+// setParams parses params into the typed fields they name. Supported targets are
+// *time.Duration (e.g. "10m"), *float64, *int32 and *bool. Unknown keys are errors.
+func setParams(params map[string]string, fields map[string]interface{}) error {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		raw := params[key]
+		var err error
+		switch dst := fields[key].(type) {
+		case *time.Duration:
+			*dst, err = time.ParseDuration(raw)
+		case *float64:
+			*dst, err = strconv.ParseFloat(raw, 64)
+		case *int32:
+			var v int64
+			v, err = strconv.ParseInt(raw, 10, 32)
+			*dst = int32(v)
+		case *bool:
+			*dst, err = strconv.ParseBool(raw)
+		default:
+			return fmt.Errorf("unknown parameter %q", key)
+		}
+		if err != nil {
+			return fmt.Errorf("parameter %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// escalationParams exposes the Escalation thresholds as rule parameters.
+func escalationParams(e *Escalation, extra map[string]interface{}) map[string]interface{} {
+	fields := map[string]interface{}{
+		"gracePeriod":   &e.GracePeriod,
+		"criticalAfter": &e.CriticalAfter,
+	}
+	for k, v := range extra {
+		fields[k] = v
+	}
+	return fields
+}
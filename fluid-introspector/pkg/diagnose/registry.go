@@ -0,0 +1,136 @@
+package diagnose
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RuleSet is an ordered list of rules. Diagnosis evaluates rules in exactly this
+// order, so a RuleSet fully determines the engine's behavior.
+type RuleSet []Rule
+
+// NewRuleSet builds a RuleSet from the given rules, preserving their order.
+// It returns an error if a rule has an empty or duplicate ID.
+func NewRuleSet(rules ...Rule) (RuleSet, error) {
+	seen := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		if r.ID() == "" {
+			return nil, fmt.Errorf("rule %T has an empty ID", r)
+		}
+		if seen[r.ID()] {
+			return nil, fmt.Errorf("duplicate rule ID %q", r.ID())
+		}
+		seen[r.ID()] = true
+	}
+	return RuleSet(append([]Rule(nil), rules...)), nil
+}
+
+// With returns a new RuleSet with the given rules appended, e.g. declarative rules
+// next to the built-ins. It returns an error on duplicate IDs.
+func (s RuleSet) With(rules ...Rule) (RuleSet, error) {
+	return NewRuleSet(append(append([]Rule(nil), s...), rules...)...)
+}
+
+// Registry holds rules in registration order. It is safe for concurrent use.
+type Registry struct {
+	mu    sync.RWMutex
+	rules []Rule
+	byID  map[string]Rule
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{byID: make(map[string]Rule)}
+}
+
+// Register appends a rule to the registry.
+// It returns an error if the rule's ID is empty or already registered.
+func (r *Registry) Register(rule Rule) error {
+	if rule == nil || rule.ID() == "" {
+		return fmt.Errorf("rule must have a non-empty ID")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.byID[rule.ID()]; exists {
+		return fmt.Errorf("rule %q is already registered", rule.ID())
+	}
+	r.rules = append(r.rules, rule)
+	r.byID[rule.ID()] = rule
+	return nil
+}
+
+// MustRegister is like Register but panics on error. Intended for init functions.
+func (r *Registry) MustRegister(rules ...Rule) {
+	for _, rule := range rules {
+		if err := r.Register(rule); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// List returns all registered rules in registration order.
+func (r *Registry) List() []Rule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]Rule(nil), r.rules...)
+}
+
+// Lookup returns the rule registered under id.
+func (r *Registry) Lookup(id string) (Rule, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rule, ok := r.byID[id]
+	return rule, ok
+}
+
+// RuleSet builds a RuleSet from the registry. Without ids it contains every
+// registered rule; otherwise only the named rules. Either way rules keep their
+// registration order, regardless of the order of ids.
+func (r *Registry) RuleSet(ids ...string) (RuleSet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(ids) == 0 {
+		return RuleSet(append([]Rule(nil), r.rules...)), nil
+	}
+
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if _, ok := r.byID[id]; !ok {
+			return nil, fmt.Errorf("unknown rule %q", id)
+		}
+		wanted[id] = true
+	}
+
+	var set RuleSet
+	for _, rule := range r.rules {
+		if wanted[rule.ID()] {
+			set = append(set, rule)
+		}
+	}
+	return set, nil
+}
+
+// DefaultRegistry holds the built-in rules. Library consumers may register
+// site-specific rules here; they run after the built-ins, in registration order.
+var DefaultRegistry = NewRegistry()
+
+func init() {
+	DefaultRegistry.MustRegister(builtinRules...)
+}
+
+// Register adds a rule to the DefaultRegistry.
+func Register(rule Rule) error {
+	return DefaultRegistry.Register(rule)
+}
+
+// Rules returns the rules of the DefaultRegistry in execution order.
+func Rules() []Rule {
+	return DefaultRegistry.List()
+}
+
+// Lookup finds a rule in the DefaultRegistry by ID.
+func Lookup(id string) (Rule, bool) {
+	return DefaultRegistry.Lookup(id)
+}
@@ -0,0 +1,127 @@
+package diagnose
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Remediation plans turn a finding into pre-filled steps. They never change the
+// cluster: commands only read, and patches are printed for the user to review and
+// apply. Rules knowing more than the evidence (a container, a limit) build their own
+// plan; every other finding gets the inspection steps of its evidence.
+
+// planRemediation gives each finding without a plan the inspection steps of its evidence.
+func planRemediation(g *types.ResourceGraph, hints []types.FailureHint) {
+	for i := range hints {
+		if len(hints[i].Remediation) == 0 {
+			hints[i].Remediation = inspectSteps(g, hints[i].Evidence)
+		}
+	}
+}
+
+// inspectSteps returns the read commands showing the resource named by the evidence.
+func inspectSteps(g *types.ResourceGraph, ev types.Evidence) []types.RemediationStep {
+	if ev.Name == "" {
+		return nil
+	}
+	ns := namespaceFlag(g)
+	switch ev.Kind {
+	case "Pod":
+		steps := []types.RemediationStep{{
+			Description: "Read the pod's conditions, container states and events",
+			Command:     fmt.Sprintf("kubectl describe pod %s%s", ev.Name, ns),
+		}}
+		if p, ok := findPod(g, ev.Name); ok && p.Restarts > 0 {
+			return append(steps, types.RemediationStep{
+				Description: "Read the logs of the previous run",
+				Command:     fmt.Sprintf("kubectl logs %s --all-containers --previous%s", ev.Name, ns),
+			})
+		}
+		return append(steps, types.RemediationStep{
+			Description: "Read the logs",
+			Command:     fmt.Sprintf("kubectl logs %s --all-containers%s", ev.Name, ns),
+		})
+	case "StatefulSet", "DaemonSet":
+		return []types.RemediationStep{{
+			Description: fmt.Sprintf("Read the %s's status and events", ev.Kind),
+			Command:     fmt.Sprintf("kubectl describe %s %s%s", strings.ToLower(ev.Kind), ev.Name, ns),
+		}}
+	case "Dataset":
+		return []types.RemediationStep{{
+			Description: "Read the Dataset's status conditions",
+			Command:     fmt.Sprintf("kubectl get dataset %s%s -o yaml", ev.Name, ns),
+		}}
+	case "PersistentVolumeClaim":
+		return []types.RemediationStep{{
+			Description: "Read the PVC's events",
+			Command:     fmt.Sprintf("kubectl describe pvc %s%s", ev.Name, ns),
+		}}
+	case "Node":
+		return []types.RemediationStep{
+			{Description: "Read the node's conditions, taints and allocated resources", Command: fmt.Sprintf("kubectl describe node %s", ev.Name)},
+			{Description: "List the pods of the Dataset's namespace on the node", Command: fmt.Sprintf("kubectl get pods%s -o wide --field-selector spec.nodeName=%s", ns, ev.Name)},
+		}
+	case "Secret":
+		return []types.RemediationStep{{
+			Description: "List the Secret's keys; describe does not print values",
+			Command:     fmt.Sprintf("kubectl describe secret %s%s", ev.Name, ns),
+		}}
+	case "Namespace":
+		return []types.RemediationStep{{
+			Description: "List the workloads and their images",
+			Command:     fmt.Sprintf("kubectl get deployments,daemonsets -n %s -o wide", ev.Name),
+		}}
+	}
+	if strings.HasSuffix(ev.Kind, "Runtime") {
+		return []types.RemediationStep{{
+			Description: fmt.Sprintf("Read the %s's spec and status", ev.Kind),
+			Command:     fmt.Sprintf("kubectl get %s %s%s -o yaml", strings.ToLower(ev.Kind), ev.Name, ns),
+		}}
+	}
+	return nil
+}
+
+// containerLogsStep reads the logs of one container, of its previous run if it restarted.
+func containerLogsStep(g *types.ResourceGraph, pod string, c types.ContainerInfo) types.RemediationStep {
+	if c.RestartCount > 0 {
+		return types.RemediationStep{
+			Description: fmt.Sprintf("Read the logs of the previous run of %s", c.Name),
+			Command:     fmt.Sprintf("kubectl logs %s -c %s --previous%s", pod, c.Name, namespaceFlag(g)),
+		}
+	}
+	return types.RemediationStep{
+		Description: fmt.Sprintf("Read the logs of %s", c.Name),
+		Command:     fmt.Sprintf("kubectl logs %s -c %s%s", pod, c.Name, namespaceFlag(g)),
+	}
+}
+
+// memoryLimitPatch suggests a memory limit for a runtime component in the Runtime spec.
+func memoryLimitPatch(g *types.ResourceGraph, component string, limit resource.Quantity, description string) types.RemediationStep {
+	return types.RemediationStep{
+		Description: description,
+		Patch: &types.Patch{
+			Kind:      runtimeKind(g),
+			Name:      g.Runtime.Name,
+			Namespace: g.Dataset.Namespace,
+			YAML:      fmt.Sprintf("spec:\n  %s:\n    resources:\n      limits:\n        memory: %s\n", componentField(component), limit.String()),
+		},
+	}
+}
+
+// findPod looks a runtime pod up by name.
+func findPod(g *types.ResourceGraph, name string) (types.PodInfo, bool) {
+	if g.Runtime == nil {
+		return types.PodInfo{}, false
+	}
+	for _, rc := range runtimeComponents(g.Runtime) {
+		for _, p := range rc.info.Pods {
+			if p.Name == name {
+				return p, true
+			}
+		}
+	}
+	return types.PodInfo{}, false
+}
@@ -2,144 +2,629 @@ package diagnose
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // Rule represents a single diagnostic condition that can check the resource graph.
+// Evaluate returns zero or more findings; a rule may report each affected pod or node separately.
 type Rule interface {
 	ID() string
-	Evaluate(graph *types.ResourceGraph) *types.FailureHint
+	Evaluate(graph *types.ResourceGraph) []types.FailureHint
 }
 
-// Rules registry - deterministic order
-var rules = []Rule{
-	&DatasetNotBoundRule{},
-	&RuntimeMissingRule{},
-	&MasterNotReadyRule{},
-	&WorkerPartiallyReadyRule{},
-	&FuseMissingRule{},
-	&PVCNotBoundRule{}, // Renamed from PVCPendingRule
-}
+// Built-in rules, registered into DefaultRegistry in this (deterministic) order.
+var builtinRules = append([]Rule{
+	&DatasetNotBoundRule{Escalation: DefaultEscalation},
+	&RuntimeMissingRule{Escalation: DefaultEscalation},
+	&MasterNotReadyRule{Escalation: DefaultEscalation},
+	&WorkerPartiallyReadyRule{Escalation: DefaultEscalation},
+	&FuseMissingRule{Escalation: DefaultEscalation},
+	&PVCNotBoundRule{Escalation: DefaultEscalation}, // Renamed from PVCPendingRule
+	&OOMKilledRule{},
+	&PodCrashLoopBackOffRule{},
+	&PodImagePullFailedRule{},
+	&PodConfigErrorRule{},
+	&PodInitFailedRule{},
+	&PodEvictedRule{},
+	&PodUnschedulableRule{Escalation: DefaultEscalation},
+	&HostPortConflictRule{},
+	&HostPathConflictRule{},
+	&TieredStoreMemExceedsLimitRule{},
+	&TieredStoreExceedsNodeAllocatableRule{},
+	&TieredStoreDiskPressureRule{},
+	&RuntimeWorkloadDriftRule{},
+	&ImageVersionSkewRule{},
+	&SilenceAnnotationInvalidRule{},
+	// Runtime packs, evaluated on their runtime types only.
+	&AlluxioMasterQuorumRule{},
+	&AlluxioUFSCredentialsInvalidRule{},
+	&JuiceFSMetaURLInvalidRule{},
+	&JuiceFSStorageCredentialsInvalidRule{},
+	&JindoOSSConfigInvalidRule{},
+	&ThinProfileMissingRule{},
+	&ThinFuseImageMissingRule{},
+	// Log signatures, evaluated on sampled pod logs; see logsignatures.go.
+}, builtinLogRules()...)
 
 // ----------------------------------------------------------------------------
 // Rule Implementations
 // ----------------------------------------------------------------------------
 
 // DATASET_NOT_BOUND
-type DatasetNotBoundRule struct{}
+type DatasetNotBoundRule struct {
+	Escalation Escalation
+}
 
 func (r *DatasetNotBoundRule) ID() string { return "DATASET_NOT_BOUND" }
 
-func (r *DatasetNotBoundRule) Evaluate(g *types.ResourceGraph) *types.FailureHint {
-	if g.Dataset.Status != "Bound" {
-		return &types.FailureHint{
-			ID:         r.ID(),
-			Severity:   types.SeverityCritical,
-			Component:  "Dataset",
-			Evidence:   types.Evidence{Kind: "Dataset", Name: g.Dataset.Name, Detail: fmt.Sprintf("Phase: %s, Status: %s", g.Dataset.Phase, g.Dataset.Status)},
+func (r *DatasetNotBoundRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Dataset not bound",
+		Description: "The Dataset CR exists but is not in a Bound state.",
+		Severity:    "Critical",
+		Component:   "Dataset",
+		Checks:      "The Dataset's status. Escalates with the time since its last condition transition: Info during the grace period, Warning until it has held for the critical threshold.",
+		Causes:      []string{"No Runtime with the Dataset's name and namespace exists", "The Runtime's master is not serving yet", "The Runtime kind does not support the Dataset's mounts"},
+		Remediation: []string{"kubectl get dataset <name> -n <ns> -o yaml and read status.conditions", "Check that a Runtime with the same name exists: kubectl get alluxioruntime,jindoruntime,juicefsruntime -n <ns>", "Check the Runtime controller logs in fluid-system"},
+		Related:     []string{"RUNTIME_MISSING", "MASTER_NOT_READY", "PVC_NOT_BOUND"},
+	}
+}
+
+// The Dataset cannot bind without a Runtime whose master is serving, or one that
+// cannot set up its file system.
+func (r *DatasetNotBoundRule) CausedBy() []string {
+	return []string{"RUNTIME_MISSING", "MASTER_NOT_READY", "JUICEFS_METAURL_INVALID", "JUICEFS_STORAGE_CREDENTIALS_INVALID", "JINDO_OSS_CONFIG_INVALID", "ALLUXIO_UFS_CREDENTIALS_INVALID", "THIN_PROFILE_MISSING"}
+}
+
+func (r *DatasetNotBoundRule) Configure(params map[string]string) (Rule, error) {
+	c := *r
+	return &c, setParams(params, escalationParams(&c.Escalation, nil))
+}
+
+func (r *DatasetNotBoundRule) withSeverity(sev types.SeverityLevel) Rule {
+	c := *r
+	c.Escalation.Severity = sev
+	return &c
+}
+
+func (r *DatasetNotBoundRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	if g.Dataset != nil && g.Dataset.Status != "Bound" {
+		severity, context := r.Escalation.severity(g, types.SeverityCritical, since(g.Dataset.CreationTimestamp, g.Dataset.LastTransitionTime))
+		return []types.FailureHint{{
+			ID:        r.ID(),
+			Severity:  severity,
+			Component: "Dataset",
+			Evidence: factEvidence("Dataset", g.Dataset.Name, "Phase: {phase}, Status: {status}",
+				fact("phase", g.Dataset.Phase), fact("status", g.Dataset.Status)),
 			Suggestion: "Check if a Runtime with the same name exists and is compatible.",
-		}
+			Context:    context,
+		}}
 	}
 	return nil
 }
 
 // RUNTIME_MISSING
-type RuntimeMissingRule struct{}
+type RuntimeMissingRule struct {
+	Escalation Escalation
+}
 
 func (r *RuntimeMissingRule) ID() string { return "RUNTIME_MISSING" }
 
-func (r *RuntimeMissingRule) Evaluate(g *types.ResourceGraph) *types.FailureHint {
-	if g.Runtime == nil {
-		return &types.FailureHint{
+func (r *RuntimeMissingRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Runtime missing",
+		Description: "No Runtime CR was found for the Dataset.",
+		Severity:    "Critical",
+		Component:   "Runtime",
+		Checks:      "Whether a Runtime of a known kind with the Dataset's name exists in its namespace. Escalates with the Dataset's age.",
+		Causes:      []string{"The Runtime was never created, or created under another name or namespace", "The Runtime was deleted"},
+		Remediation: []string{"Create a Runtime CR (e.g. AlluxioRuntime, JindoRuntime) with the Dataset's name in its namespace", "kubectl get datasets,alluxioruntimes,jindoruntimes,juicefsruntimes -n <ns>"},
+		Related:     []string{"DATASET_NOT_BOUND"},
+	}
+}
+
+func (r *RuntimeMissingRule) Configure(params map[string]string) (Rule, error) {
+	c := *r
+	return &c, setParams(params, escalationParams(&c.Escalation, nil))
+}
+
+func (r *RuntimeMissingRule) withSeverity(sev types.SeverityLevel) Rule {
+	c := *r
+	c.Escalation.Severity = sev
+	return &c
+}
+
+func (r *RuntimeMissingRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	if g.Runtime == nil && g.Dataset != nil {
+		// A Runtime is usually created right after its Dataset, so measure from the Dataset's creation.
+		severity, context := r.Escalation.severity(g, types.SeverityCritical, g.Dataset.CreationTimestamp)
+		return []types.FailureHint{{
 			ID:         r.ID(),
-			Severity:   types.SeverityCritical,
+			Severity:   severity,
 			Component:  "Runtime",
 			Evidence:   types.Evidence{Kind: "Runtime", Name: g.Dataset.Name, Detail: "Runtime object is missing from graph."},
 			Suggestion: "Create a Runtime CR (e.g., AlluxioRuntime, JindoRuntime) matching the Dataset.",
-		}
+			Context:    context,
+		}}
 	}
 	return nil
 }
 
 // MASTER_NOT_READY
-type MasterNotReadyRule struct{}
+// Reports each unready master pod; falls back to the StatefulSet when pods are unknown.
+type MasterNotReadyRule struct {
+	Escalation Escalation
+}
 
 func (r *MasterNotReadyRule) ID() string { return "MASTER_NOT_READY" }
 
-func (r *MasterNotReadyRule) Evaluate(g *types.ResourceGraph) *types.FailureHint {
+func (r *MasterNotReadyRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Master not ready",
+		Description: "The Runtime Master StatefulSet is not fully ready.",
+		Severity:    "Critical",
+		Component:   "Runtime/Master",
+		Checks:      "Ready against desired replicas of the master StatefulSet, with one finding per unready master pod. Components scaled to zero on purpose are skipped.",
+		Causes:      []string{"The master crashes on startup, e.g. on a bad UFS mount or journal", "The master pod cannot be scheduled", "The master image cannot be pulled"},
+		Remediation: []string{"kubectl describe pod <name>-master-0 -n <ns>", "kubectl logs <name>-master-0 -n <ns> --all-containers"},
+		Related:     []string{"POD_CRASHLOOP_BACKOFF", "POD_UNSCHEDULABLE", "OOM_KILLED"},
+	}
+}
+
+// podFailureCauses are the rules reporting why a pod is not ready. They explain
+// the readiness finding of the same pod.
+var podFailureCauses = []string{
+	"POD_UNSCHEDULABLE", "POD_IMAGE_PULL_FAILED", "POD_CONFIG_ERROR", "POD_INIT_FAILED",
+	"POD_CRASHLOOP_BACKOFF", "POD_EVICTED", "OOM_KILLED",
+}
+
+func (r *MasterNotReadyRule) CausedBy() []string { return podFailureCauses }
+
+func (r *MasterNotReadyRule) Configure(params map[string]string) (Rule, error) {
+	c := *r
+	return &c, setParams(params, escalationParams(&c.Escalation, nil))
+}
+
+func (r *MasterNotReadyRule) withSeverity(sev types.SeverityLevel) Rule {
+	c := *r
+	c.Escalation.Severity = sev
+	return &c
+}
+
+func (r *MasterNotReadyRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
 	if g.Runtime != nil && g.Runtime.Master != nil {
-		if g.Runtime.Master.Ready != g.Runtime.Master.Replicas {
-			return &types.FailureHint{
+		master := g.Runtime.Master
+		if master.Ready != master.Replicas && !scaledDown(master) {
+			severity, context := r.Escalation.severity(g, types.SeverityCritical, since(master.CreationTimestamp, master.LastTransitionTime))
+			hint := types.FailureHint{
 				ID:         r.ID(),
-				Severity:   types.SeverityCritical,
+				Severity:   severity,
 				Component:  "Runtime/Master",
-				Evidence:   types.Evidence{Kind: "StatefulSet", Name: g.Runtime.Master.Name, Detail: fmt.Sprintf("Ready replicas: %d/%d", g.Runtime.Master.Ready, g.Runtime.Master.Replicas)},
+				Evidence:   replicaEvidence(runtimeComponent{"Runtime/Master", master}),
 				Suggestion: "Check Master pod logs for startup errors or scheduling issues.",
+				Context:    context,
+			}
+			if hints := unreadyPodHints(g, r.Escalation, types.SeverityCritical, hint, master); len(hints) > 0 {
+				return hints
 			}
+			return []types.FailureHint{hint}
 		}
 	}
 	return nil
 }
 
 // WORKER_PARTIALLY_READY
-type WorkerPartiallyReadyRule struct{}
+// Reports each unready worker pod; falls back to the workload when pods are unknown.
+type WorkerPartiallyReadyRule struct {
+	Escalation         Escalation
+	CriticalReadyRatio float64 // Critical instead of Warning when fewer than this fraction of workers is ready (0 disables)
+	RestartThreshold   int32   // Also report ready workers that restarted at least this often (0 disables)
+}
 
 func (r *WorkerPartiallyReadyRule) ID() string { return "WORKER_PARTIALLY_READY" }
 
-func (r *WorkerPartiallyReadyRule) Evaluate(g *types.ResourceGraph) *types.FailureHint {
-	if g.Runtime != nil && g.Runtime.Worker != nil {
-		if g.Runtime.Worker.Ready < g.Runtime.Worker.Replicas {
-			return &types.FailureHint{
-				ID:         r.ID(),
-				Severity:   types.SeverityWarning,
-				Component:  "Runtime/Worker",
-				Evidence:   types.Evidence{Kind: "StatefulSet/DaemonSet", Name: g.Runtime.Worker.Name, Detail: fmt.Sprintf("Ready replicas: %d/%d", g.Runtime.Worker.Ready, g.Runtime.Worker.Replicas)},
-				Suggestion: "Check individual Worker pods for OOMKilled or CrashLoopBackOff.",
+func (r *WorkerPartiallyReadyRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Workers partially ready",
+		Description: "Some Worker pods are not ready (e.g., OOMKilled, CrashLoop).",
+		Severity:    "Critical/Warning",
+		Component:   "Runtime/Worker",
+		Checks:      "Ready against desired worker replicas, with one finding per unready worker pod. Critical below the configured ready ratio; optionally also reports ready workers restarting often.",
+		Causes:      []string{"Workers are OOMKilled or crash-looping", "Workers cannot be scheduled", "The master is not ready, so workers cannot register"},
+		Remediation: []string{"kubectl get pods -n <ns> -l role=<runtime>-worker -o wide", "kubectl logs <worker pod> -n <ns> --previous"},
+		Related:     []string{"OOM_KILLED", "POD_CRASHLOOP_BACKOFF", "POD_UNSCHEDULABLE", "MASTER_NOT_READY"},
+	}
+}
+
+// Workers register with the master; they cannot become ready while it is down.
+func (r *WorkerPartiallyReadyRule) CausedBy() []string {
+	return append([]string{"MASTER_NOT_READY"}, podFailureCauses...)
+}
+
+func (r *WorkerPartiallyReadyRule) Configure(params map[string]string) (Rule, error) {
+	c := *r
+	return &c, setParams(params, escalationParams(&c.Escalation, map[string]interface{}{
+		"criticalReadyRatio": &c.CriticalReadyRatio,
+		"restartThreshold":   &c.RestartThreshold,
+	}))
+}
+
+func (r *WorkerPartiallyReadyRule) withSeverity(sev types.SeverityLevel) Rule {
+	c := *r
+	c.Escalation.Severity = sev
+	return &c
+}
+
+func (r *WorkerPartiallyReadyRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	if g.Runtime == nil || g.Runtime.Worker == nil {
+		return nil
+	}
+	worker := g.Runtime.Worker
+
+	var hints []types.FailureHint
+	if worker.Ready < worker.Replicas {
+		base := types.SeverityWarning
+		if float64(worker.Ready) < r.CriticalReadyRatio*float64(worker.Replicas) {
+			base = types.SeverityCritical
+		}
+		severity, context := r.Escalation.severity(g, base, since(worker.CreationTimestamp, worker.LastTransitionTime))
+		hint := types.FailureHint{
+			ID:         r.ID(),
+			Severity:   severity,
+			Component:  "Runtime/Worker",
+			Evidence:   replicaEvidence(runtimeComponent{"Runtime/Worker", worker}),
+			Suggestion: "Check individual Worker pods for OOMKilled or CrashLoopBackOff.",
+			Context:    context,
+		}
+		hints = unreadyPodHints(g, r.Escalation, base, hint, worker)
+		if len(hints) == 0 {
+			hints = []types.FailureHint{hint}
+		}
+	}
+
+	if r.RestartThreshold > 0 {
+		for _, p := range worker.Pods {
+			if p.Ready && p.Restarts >= r.RestartThreshold {
+				hints = append(hints, types.FailureHint{
+					ID:         r.ID(),
+					Severity:   r.Escalation.base(types.SeverityWarning),
+					Component:  "Runtime/Worker",
+					Evidence:   podEvidence(p),
+					Suggestion: "Worker is ready but restarting repeatedly; inspect the previous container logs.",
+					Context:    fmt.Sprintf("Restart threshold: %d", r.RestartThreshold),
+				})
 			}
 		}
 	}
-	return nil
+	return hints
 }
 
 // FUSE_MISSING
-type FuseMissingRule struct{}
+// Reports each node running pods that mount the Dataset without a ready fuse pod. Fuse
+// pods elsewhere are not needed: an on-demand fuse legitimately runs on few or no nodes.
+// Without the mounting pods, reports each node whose fuse pod is not ready, falling back
+// to the DaemonSet when pods are unknown.
+type FuseMissingRule struct {
+	Escalation Escalation
+}
 
 func (r *FuseMissingRule) ID() string { return "FUSE_MISSING" }
 
-func (r *FuseMissingRule) Evaluate(g *types.ResourceGraph) *types.FailureHint {
+func (r *FuseMissingRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Fuse missing where the Dataset is mounted",
+		Description: "A node runs pods mounting the Dataset but no ready fuse pod, e.g. an on-demand fuse whose node label is missing. Without the mounting pods: the Fuse DaemonSet has 0 ready replicas.",
+		Severity:    "Warning",
+		Component:   "Runtime/Fuse",
+		Checks:      "For each node running pods that mount the Dataset's PVC, whether a ready fuse pod runs there, and if not, whether the node lacks the on-demand fuse label or does not match the fuse nodeSelector. Escalates with the age of the waiting pods.",
+		Causes:      []string{"The CSI plugin did not label the node for an on-demand fuse", "The fuse nodeSelector or tolerations exclude the node", "The fuse pod on the node is failing"},
+		Remediation: []string{"kubectl get pods -n <ns> -o wide | grep fuse", "kubectl get node <node> --show-labels", "kubectl logs -n fluid-system <csi-nodeplugin-fluid pod on the node> -c plugins"},
+		Related:     []string{"MASTER_NOT_READY", "POD_CRASHLOOP_BACKOFF"},
+	}
+}
+
+// Fuse clients connect to the master on startup, and need an image to start at all.
+func (r *FuseMissingRule) CausedBy() []string {
+	return []string{"MASTER_NOT_READY", "THIN_FUSE_IMAGE_MISSING"}
+}
+
+func (r *FuseMissingRule) Configure(params map[string]string) (Rule, error) {
+	c := *r
+	return &c, setParams(params, escalationParams(&c.Escalation, nil))
+}
+
+func (r *FuseMissingRule) withSeverity(sev types.SeverityLevel) Rule {
+	c := *r
+	c.Escalation.Severity = sev
+	return &c
+}
+
+func (r *FuseMissingRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
 	if g.Runtime != nil && g.Runtime.Fuse != nil {
-		if g.Runtime.Fuse.Ready == 0 && g.Runtime.Fuse.Replicas > 0 {
+		fuse := g.Runtime.Fuse
+		if g.Consumers != nil {
+			return r.consumerHints(g, fuse)
+		}
+		if fuse.Ready == 0 && fuse.Replicas > 0 {
 			// If desired replicas > 0 but none are ready, it's considered missing or completely broken.
-			return &types.FailureHint{
+			severity, context := r.Escalation.severity(g, types.SeverityWarning, since(fuse.CreationTimestamp, fuse.LastTransitionTime))
+			hint := types.FailureHint{
 				ID:         r.ID(),
-				Severity:   types.SeverityWarning,
+				Severity:   severity,
 				Component:  "Runtime/Fuse",
-				Evidence:   types.Evidence{Kind: "DaemonSet", Name: g.Runtime.Fuse.Name, Detail: fmt.Sprintf("Ready replicas: %d/%d", g.Runtime.Fuse.Ready, g.Runtime.Fuse.Replicas)},
+				Evidence:   replicaEvidence(runtimeComponent{"Runtime/Fuse", fuse}),
 				Suggestion: "Check DaemonSet node selectors and tolerations. Ensure nodes have capacity.",
+				Context:    context,
+			}
+			if hints := unreadyPodHints(g, r.Escalation, types.SeverityWarning, hint, fuse); len(hints) > 0 {
+				return hints
 			}
+			return []types.FailureHint{hint}
 		}
 	}
 	return nil
 }
 
 // PVC_NOT_BOUND
-type PVCNotBoundRule struct{}
+type PVCNotBoundRule struct {
+	Escalation Escalation
+}
 
 func (r *PVCNotBoundRule) ID() string { return "PVC_NOT_BOUND" }
 
-func (r *PVCNotBoundRule) Evaluate(g *types.ResourceGraph) *types.FailureHint {
+func (r *PVCNotBoundRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "PVC not bound",
+		Description: "The underlying PersistentVolumeClaim is not Bound.",
+		Severity:    "Critical",
+		Component:   "Infrastructure/PVC",
+		Checks:      "The phase of the PersistentVolumeClaim named after the Dataset. Escalates with the PVC's age.",
+		Causes:      []string{"The Dataset is not bound yet, so Fluid has not created the PersistentVolume", "The PersistentVolume was deleted or is bound elsewhere"},
+		Remediation: []string{"kubectl describe pvc <name> -n <ns>", "kubectl get pv | grep <ns>-<name>"},
+		Related:     []string{"DATASET_NOT_BOUND", "RUNTIME_MISSING"},
+	}
+}
+
+// Fluid creates the PV/PVC only after the Dataset is bound.
+func (r *PVCNotBoundRule) CausedBy() []string {
+	return []string{"RUNTIME_MISSING", "DATASET_NOT_BOUND"}
+}
+
+func (r *PVCNotBoundRule) Configure(params map[string]string) (Rule, error) {
+	c := *r
+	return &c, setParams(params, escalationParams(&c.Escalation, nil))
+}
+
+func (r *PVCNotBoundRule) withSeverity(sev types.SeverityLevel) Rule {
+	c := *r
+	c.Escalation.Severity = sev
+	return &c
+}
+
+func (r *PVCNotBoundRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
 	if g.Infrastructure != nil && g.Infrastructure.PVC != nil {
 		if !strings.EqualFold(g.Infrastructure.PVC.Status, "Bound") {
-			return &types.FailureHint{
+			severity, context := r.Escalation.severity(g, types.SeverityCritical, g.Infrastructure.PVC.CreationTimestamp)
+			return []types.FailureHint{{
 				ID:         r.ID(),
-				Severity:   types.SeverityCritical,
+				Severity:   severity,
 				Component:  "Infrastructure/PVC",
-				Evidence:   types.Evidence{Kind: "PersistentVolumeClaim", Name: g.Infrastructure.PVC.Name, Detail: fmt.Sprintf("Status: %s", g.Infrastructure.PVC.Status)},
+				Evidence:   factEvidence("PersistentVolumeClaim", g.Infrastructure.PVC.Name, "Status: {status}", fact("status", g.Infrastructure.PVC.Status)),
 				Suggestion: "Check PersistentVolume availability or StorageClass configuration.",
+				Context:    context,
+			}}
+		}
+	}
+	return nil
+}
+
+// OOM_KILLED
+// Reports each runtime container whose current or last termination was OOMKilled.
+// Critical while the pod is down; Warning once it recovered, since it will likely recur.
+type OOMKilledRule struct{}
+
+func (r *OOMKilledRule) ID() string { return "OOM_KILLED" }
+
+func (r *OOMKilledRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Container OOMKilled",
+		Description: "A runtime container's current or last termination was OOMKilled. Critical while the pod is down, Warning once it recovered. Names the memory limit and the runtime spec field to raise.",
+		Severity:    "Critical/Warning",
+		Component:   "Runtime pods",
+		Checks:      "The current and last termination state of every runtime container.",
+		Causes:      []string{"The memory limit is too low for the workload", "A MEM tiered store level larger than the worker's memory limit", "JVM heap settings above the container limit"},
+		Remediation: []string{"Raise spec.<component>.resources.limits.memory on the Runtime", "Lower the MEM quota in spec.tieredstore.levels", "kubectl describe pod <pod> -n <ns> and read Last State"},
+		Related:     []string{"TIEREDSTORE_MEM_EXCEEDS_LIMIT", "POD_CRASHLOOP_BACKOFF"},
+	}
+}
+
+// A MEM cache level larger than the memory limit fills the worker's cgroup.
+func (r *OOMKilledRule) CausedBy() []string { return []string{"TIEREDSTORE_MEM_EXCEEDS_LIMIT"} }
+
+func (r *OOMKilledRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	if g.Runtime == nil {
+		return nil
+	}
+
+	var hints []types.FailureHint
+	for _, rc := range runtimeComponents(g.Runtime) {
+		for _, p := range rc.info.Pods {
+			for _, c := range p.Containers {
+				current := isOOMKilled(c.State)
+				if !current && !isOOMKilled(c.LastState) {
+					continue
+				}
+				severity := types.SeverityWarning
+				if current || !p.Ready {
+					severity = types.SeverityCritical
+				}
+				limit := c.MemoryLimit
+				if limit == "" {
+					limit = "none"
+				}
+				kind := "Container"
+				if c.Init {
+					kind = "Init container"
+				}
+				hints = append(hints, types.FailureHint{
+					ID:        r.ID(),
+					Severity:  severity,
+					Component: rc.component,
+					Evidence: factEvidence("Pod", p.Name, kind+" {container} OOMKilled, Memory limit: {memoryLimit}, Restarts: {restarts}",
+						fact("container", c.Name), fact("memoryLimit", limit), fact("restarts", c.RestartCount)),
+					Suggestion:  oomSuggestion(rc.component, g.Runtime.Type),
+					Remediation: oomRemediation(g, rc.component, p, c),
+				})
 			}
 		}
 	}
+	return hints
+}
+
+// oomRemediation reads the killed run and, for a known limit, suggests doubling it.
+func oomRemediation(g *types.ResourceGraph, component string, p types.PodInfo, c types.ContainerInfo) []types.RemediationStep {
+	steps := []types.RemediationStep{
+		{Description: "Read the container's last state and the node's memory pressure", Command: fmt.Sprintf("kubectl describe pod %s%s", p.Name, namespaceFlag(g))},
+		containerLogsStep(g, p.Name, c),
+	}
+	limit, err := resource.ParseQuantity(c.MemoryLimit)
+	if err != nil || c.Init {
+		return steps
+	}
+	limit.Add(limit)
+	return append(steps, memoryLimitPatch(g, component, limit, fmt.Sprintf("Double the %s memory limit", componentField(component))))
+}
+
+func isOOMKilled(s *corev1.ContainerState) bool {
+	return s != nil && s.Terminated != nil && s.Terminated.Reason == "OOMKilled"
+}
+
+// oomSuggestion names the runtime spec field that sizes the component's memory.
+func oomSuggestion(component, runtimeType string) string {
+	if runtimeType == "" {
+		runtimeType = "Runtime"
+	}
+	switch component {
+	case "Runtime/Master":
+		return fmt.Sprintf("Raise spec.master.resources.limits.memory on the %s; master memory grows with the number of cached files.", runtimeType)
+	case "Runtime/Worker":
+		return fmt.Sprintf("Raise spec.worker.resources.limits.memory on the %s, or lower the MEM quota in spec.tieredstore.levels so the cache fits within the limit.", runtimeType)
+	default:
+		return fmt.Sprintf("Raise spec.fuse.resources.limits.memory on the %s; FUSE memory grows with concurrent reads.", runtimeType)
+	}
+}
+
+// SILENCE_ANNOTATION_INVALID
+// A malformed silence annotation silences nothing; say so instead of failing quietly.
+type SilenceAnnotationInvalidRule struct{}
+
+func (r *SilenceAnnotationInvalidRule) ID() string { return "SILENCE_ANNOTATION_INVALID" }
+
+func (r *SilenceAnnotationInvalidRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Invalid silence annotation",
+		Description: "The Dataset's silence annotation cannot be parsed, so nothing is silenced.",
+		Severity:    "Warning",
+		Component:   "Dataset",
+		Checks:      "The diagnose.fluid.io/silence annotation of the Dataset.",
+		Causes:      []string{"Malformed JSON or an unknown format in the annotation"},
+		Remediation: []string{"Use a comma-separated list of rule IDs, or a JSON list of {\"rule\", \"expires\", \"reason\"} objects"},
+	}
+}
+
+func (r *SilenceAnnotationInvalidRule) Inputs(g *types.ResourceGraph) []types.Fact {
+	return []types.Fact{
+		{Key: "silences", Value: strconv.Itoa(len(g.Dataset.Silences))},
+		{Key: "silenceError", Value: orNone(g.Dataset.SilenceError)},
+	}
+}
+
+func (r *SilenceAnnotationInvalidRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	if g.Dataset.SilenceError != "" {
+		return []types.FailureHint{{
+			ID:         r.ID(),
+			Severity:   types.SeverityWarning,
+			Component:  "Dataset",
+			Evidence:   types.Evidence{Kind: "Dataset", Name: g.Dataset.Name, Detail: g.Dataset.SilenceError},
+			Suggestion: fmt.Sprintf("Fix the %s annotation; until then no findings are silenced.", types.SilenceAnnotation),
+		}}
+	}
 	return nil
 }
+
+// ----------------------------------------------------------------------------
+// Helpers
+// ----------------------------------------------------------------------------
+
+// unreadyPodHints derives one finding per unready pod of a component from the
+// component-level hint. Each pod is escalated by its own age, so a freshly
+// rescheduled replica stays Info while a long-broken one escalates. The findings
+// also reference the workload: the component is not ready as a whole.
+// It returns nil if no pod is known to be unready.
+func unreadyPodHints(g *types.ResourceGraph, e Escalation, base types.SeverityLevel, hint types.FailureHint, c *types.ComponentInfo) []types.FailureHint {
+	var hints []types.FailureHint
+	for _, p := range c.Pods {
+		if p.Ready {
+			continue
+		}
+		h := hint
+		h.Severity, h.Context = e.severity(g, base, since(p.CreationTimestamp, p.LastTransitionTime))
+		h.Evidence = podEvidence(p)
+		h.Evidence.Objects = []types.ObjectRef{ref("Pod", p.Name), ref(hint.Evidence.Kind, hint.Evidence.Name)}
+		hints = append(hints, h)
+	}
+	return hints
+}
+
+// replicaEvidence reports the workload of a component with its ready and desired replicas.
+func replicaEvidence(rc runtimeComponent) types.Evidence {
+	return factEvidence(workloadKind(rc), rc.info.Name, "Ready replicas: {readyReplicas}/{replicas}",
+		fact("readyReplicas", rc.info.Ready), fact("replicas", rc.info.Replicas))
+}
+
+// runtimeComponent is a runtime component together with its Component label.
+type runtimeComponent struct {
+	component string
+	info      *types.ComponentInfo
+}
+
+// runtimeComponents lists the present components of a runtime: master, worker, fuse.
+func runtimeComponents(rt *types.RuntimeInfo) []runtimeComponent {
+	var out []runtimeComponent
+	for _, rc := range []runtimeComponent{
+		{"Runtime/Master", rt.Master},
+		{"Runtime/Worker", rt.Worker},
+		{"Runtime/Fuse", rt.Fuse},
+	} {
+		if rc.info != nil {
+			out = append(out, rc)
+		}
+	}
+	return out
+}
+
+// scaledDown reports whether a component is scaled to zero on purpose: its workload
+// wants no replicas (the mapper's ComponentsScaledDown state) and the Runtime spec
+// does not ask for more. Pods still terminating are then expected to go away.
+func scaledDown(c *types.ComponentInfo) bool {
+	if c.Replicas != 0 {
+		return false
+	}
+	return c.Desired == nil || c.Desired.Replicas == nil || *c.Desired.Replicas == 0
+}
+
+// podEvidence reports a pod's state, e.g. "Status: CrashLoopBackOff, Restarts: 5, Node: node-1".
+func podEvidence(p types.PodInfo) types.Evidence {
+	format := "Status: {status}, Restarts: {restarts}"
+	facts := []types.Fact{fact("status", p.Status), fact("restarts", p.Restarts)}
+	if p.Node != "" {
+		format += ", Node: {node}"
+		facts = append(facts, fact("node", p.Node))
+	}
+	return factEvidence("Pod", p.Name, format, facts...)
+}
@@ -0,0 +1,152 @@
+package diagnose
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+)
+
+// RuntimeRule is implemented by rules of a runtime pack: checks of one runtime's CRD
+// fields and components. The engine evaluates them only on graphs whose
+// RuntimeInfo.Type is listed; an empty list applies to every runtime.
+type RuntimeRule interface {
+	Rule
+	RuntimeTypes() []string
+}
+
+// appliesTo reports whether the engine should evaluate rule on g.
+func appliesTo(rule Rule, g *types.ResourceGraph) bool {
+	rr, ok := rule.(RuntimeRule)
+	if !ok || len(rr.RuntimeTypes()) == 0 {
+		return true
+	}
+	if g.Runtime == nil {
+		return false
+	}
+	for _, t := range rr.RuntimeTypes() {
+		if t == g.Runtime.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// mountsWithScheme returns the Dataset's mounts whose mount point has the scheme,
+// e.g. "oss".
+func mountsWithScheme(g *types.ResourceGraph, scheme string) []types.MountInfo {
+	var out []types.MountInfo
+	for _, m := range g.Dataset.Mounts {
+		if strings.HasPrefix(m.MountPoint, scheme+"://") {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// encryptOption finds the encrypt option of a mount by name.
+func encryptOption(m types.MountInfo, name string) (types.EncryptOption, bool) {
+	for _, o := range m.EncryptOptions {
+		if o.Name == name {
+			return o, true
+		}
+	}
+	return types.EncryptOption{}, false
+}
+
+func hasOptionKey(m types.MountInfo, key string) bool {
+	for _, k := range m.OptionKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// secretRefProblem checks that the Secret key an encrypt option reads exists. It
+// reports nothing when Secrets were not collected.
+func secretRefProblem(g *types.ResourceGraph, o types.EncryptOption) (string, bool) {
+	if g.Secrets == nil {
+		return "", false
+	}
+	for _, s := range g.Secrets {
+		if s.Name != o.SecretName {
+			continue
+		}
+		if !s.Found {
+			return fmt.Sprintf("Secret %s referenced by %s not found", o.SecretName, o.Name), true
+		}
+		for _, k := range s.Keys {
+			if k == o.SecretKey {
+				return "", false
+			}
+		}
+		return fmt.Sprintf("Secret %s has no key %s referenced by %s (keys: %s)", o.SecretName, o.SecretKey, o.Name, strings.Join(s.Keys, ", ")), true
+	}
+	return fmt.Sprintf("Secret %s referenced by %s not found", o.SecretName, o.Name), true
+}
+
+// secretEvidence reports the Secret an encrypt option reads, and the Dataset reading it.
+func secretEvidence(g *types.ResourceGraph, o types.EncryptOption, detail string) types.Evidence {
+	return types.Evidence{
+		Kind:    "Secret",
+		Name:    o.SecretName,
+		Detail:  detail,
+		Objects: []types.ObjectRef{ref("Secret", o.SecretName), ref("Dataset", g.Dataset.Name)},
+		Facts:   []types.Fact{{Key: "encryptOption", Value: o.Name}, {Key: "secretKey", Value: o.SecretKey}},
+	}
+}
+
+// mountName names a mount in evidence: its name, else its mount point.
+func mountName(m types.MountInfo) string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return m.MountPoint
+}
+
+// credentialHints checks the options of a mount that together form a credential, e.g.
+// an AccessKey pair: each must be read from an existing Secret key (Critical), not
+// given as a plain option (Warning), and set along with the others (Critical).
+func credentialHints(g *types.ResourceGraph, id string, m types.MountInfo, keys []string) []types.FailureHint {
+	var hints []types.FailureHint
+	var set, unset []string
+	for _, key := range keys {
+		if o, ok := encryptOption(m, key); ok {
+			set = append(set, key)
+			if problem, bad := secretRefProblem(g, o); bad {
+				hints = append(hints, types.FailureHint{
+					ID:         id,
+					Severity:   types.SeverityCritical,
+					Component:  "Dataset",
+					Evidence:   secretEvidence(g, o, fmt.Sprintf("Mount %s: %s", mountName(m), problem)),
+					Suggestion: fmt.Sprintf("Create the Secret %s with key %s in the Dataset's namespace, or fix valueFrom.secretKeyRef of %s.", o.SecretName, o.SecretKey, key),
+				})
+			}
+			continue
+		}
+		if !hasOptionKey(m, key) {
+			unset = append(unset, key)
+			continue
+		}
+		set = append(set, key)
+		hints = append(hints, types.FailureHint{
+			ID:         id,
+			Severity:   types.SeverityWarning,
+			Component:  "Dataset",
+			Evidence:   factEvidence("Dataset", g.Dataset.Name, "Mount {mount}: {option} is a plain option", fact("mount", mountName(m)), fact("option", key)),
+			Suggestion: fmt.Sprintf("Everyone who can read the Dataset sees the credential. Store %s in a Secret and reference it from encryptOptions.", key),
+		})
+	}
+	if len(set) > 0 && len(unset) > 0 {
+		hints = append(hints, types.FailureHint{
+			ID:        id,
+			Severity:  types.SeverityCritical,
+			Component: "Dataset",
+			Evidence: factEvidence("Dataset", g.Dataset.Name, "Mount {mount}: {option} without {missing}",
+				fact("mount", mountName(m)), fact("option", strings.Join(set, ", ")), fact("missing", strings.Join(unset, ", "))),
+			Suggestion: fmt.Sprintf("Set %s as well, from the same Secret through encryptOptions: the under storage rejects a partial credential.", strings.Join(unset, ", ")),
+		})
+	}
+	return hints
+}
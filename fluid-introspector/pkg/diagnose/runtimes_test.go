@@ -0,0 +1,204 @@
+package diagnose_test
+
+import (
+	"testing"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/diagnose"
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// packGraph is a healthy graph of the given runtime type with the Dataset's mounts.
+func packGraph(runtimeType string, mounts ...types.MountInfo) *types.ResourceGraph {
+	return &types.ResourceGraph{
+		Dataset: &types.DatasetInfo{Name: "demo-data", Namespace: "default", Status: "Bound", Mounts: mounts},
+		Runtime: &types.RuntimeInfo{
+			Name:   "demo-data",
+			Type:   runtimeType,
+			Worker: &types.ComponentInfo{Name: "demo-data-worker", Replicas: 1, Ready: 1},
+			Fuse:   &types.ComponentInfo{Name: "demo-data-fuse", Replicas: 1, Ready: 1},
+		},
+	}
+}
+
+func findings(result *types.DiagnosticResult, id string) []types.FailureHint {
+	var out []types.FailureHint
+	for _, h := range result.FailureHints {
+		if h.ID == id {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+func TestRuntimePacks_SelectedByType(t *testing.T) {
+	two := int32(2)
+	master := &types.ComponentInfo{Name: "demo-data-master", Replicas: 2, Ready: 2, Desired: &types.ComponentSpec{Replicas: &two}}
+
+	alluxio := packGraph("AlluxioRuntime")
+	alluxio.Runtime.Master = master
+	hints := findings(diagnose.Diagnose(alluxio), "ALLUXIO_MASTER_QUORUM")
+	require.Len(t, hints, 1)
+	assert.Contains(t, hints[0].Evidence.Detail, "Master replicas: 2")
+	require.NotNil(t, hints[0].Remediation[1].Patch)
+	assert.Equal(t, "spec:\n  master:\n    replicas: 3\n", hints[0].Remediation[1].Patch.YAML)
+
+	// The same master on a JindoRuntime is not an Alluxio journal.
+	jindo := packGraph("JindoRuntime")
+	jindo.Runtime.Master = master
+	assert.Empty(t, findings(diagnose.Diagnose(jindo), "ALLUXIO_MASTER_QUORUM"))
+
+	// Profiles keep the selection.
+	set, err := diagnose.DefaultRegistry.RuleSet("ALLUXIO_MASTER_QUORUM")
+	require.NoError(t, err)
+	profile := &diagnose.Profile{Name: "strict", Rules: map[string]diagnose.RuleOverride{"ALLUXIO_MASTER_QUORUM": {Severity: types.SeverityCritical}}}
+	result, err := diagnose.Run(jindo, diagnose.WithRuleSet(set), diagnose.WithProfile(profile))
+	require.NoError(t, err)
+	assert.True(t, result.IsHealthy)
+
+	m := diagnose.Describe(set[0])
+	assert.Equal(t, []string{"AlluxioRuntime"}, m.Runtimes)
+	assert.False(t, m.Fleet)
+}
+
+func TestRuntimePacks_JuiceFSMetaURL(t *testing.T) {
+	metaurl := types.EncryptOption{Name: "metaurl", SecretName: "jfs-secret", SecretKey: "metaurl"}
+
+	// No metaurl at all.
+	g := packGraph("JuiceFSRuntime", types.MountInfo{Name: "jfs", MountPoint: "juicefs:///"})
+	hints := findings(diagnose.Diagnose(g), "JUICEFS_METAURL_INVALID")
+	require.Len(t, hints, 1)
+	assert.Equal(t, types.SeverityCritical, hints[0].Severity)
+	assert.Contains(t, hints[0].Evidence.Detail, "no metaurl or token")
+
+	// The referenced key is missing; the finding names the keys, not their values.
+	g = packGraph("JuiceFSRuntime", types.MountInfo{Name: "jfs", MountPoint: "juicefs:///", EncryptOptions: []types.EncryptOption{metaurl}})
+	g.Secrets = []types.SecretInfo{{Name: "jfs-secret", Found: true, Keys: []string{"access-key", "meta-url"}}}
+	hints = findings(diagnose.Diagnose(g), "JUICEFS_METAURL_INVALID")
+	require.Len(t, hints, 1)
+	assert.Equal(t, "Secret", hints[0].Evidence.Kind)
+	assert.Equal(t, "Mount jfs: Secret jfs-secret has no key metaurl referenced by metaurl (keys: access-key, meta-url)", hints[0].Evidence.Detail)
+	assert.Equal(t, []types.ObjectRef{
+		{APIVersion: "v1", Kind: "Secret", Namespace: "default", Name: "jfs-secret"},
+		{APIVersion: "data.fluid.io/v1alpha1", Kind: "Dataset", Namespace: "default", Name: "demo-data"},
+	}, hints[0].Evidence.Objects)
+	assert.Equal(t, "kubectl describe secret jfs-secret -n default", hints[0].Remediation[0].Command)
+
+	// Fine once the key exists, and not judged when Secrets could not be read.
+	g.Secrets[0].Keys = []string{"metaurl"}
+	assert.Empty(t, findings(diagnose.Diagnose(g), "JUICEFS_METAURL_INVALID"))
+	g.Secrets = nil
+	assert.Empty(t, findings(diagnose.Diagnose(g), "JUICEFS_METAURL_INVALID"))
+
+	// A plain option works but leaks the password.
+	g = packGraph("JuiceFSRuntime", types.MountInfo{MountPoint: "juicefs:///", OptionKeys: []string{"metaurl"}})
+	hints = findings(diagnose.Diagnose(g), "JUICEFS_METAURL_INVALID")
+	require.Len(t, hints, 1)
+	assert.Equal(t, types.SeverityWarning, hints[0].Severity)
+}
+
+func TestRuntimePacks_JindoOSS(t *testing.T) {
+	g := packGraph("JindoRuntime",
+		types.MountInfo{Name: "oss", MountPoint: "oss://bucket/data", OptionKeys: []string{"fs.oss.accessKeyId"},
+			EncryptOptions: []types.EncryptOption{{Name: "fs.oss.accessKeySecret", SecretName: "oss-creds", SecretKey: "secret"}}},
+		types.MountInfo{Name: "hdfs", MountPoint: "hdfs://namenode:9000/data"},
+	)
+	g.Secrets = []types.SecretInfo{{Name: "oss-creds"}}
+
+	var details []string
+	for _, h := range findings(diagnose.Diagnose(g), "JINDO_OSS_CONFIG_INVALID") {
+		details = append(details, string(h.Severity)+": "+h.Evidence.Detail)
+	}
+	assert.ElementsMatch(t, []string{
+		"Critical: Mount oss: Secret oss-creds referenced by fs.oss.accessKeySecret not found",
+		"Warning: Mount oss: fs.oss.accessKeyId is a plain option",
+		"Warning: Mount oss: no fs.oss.endpoint option",
+	}, details)
+
+	// Half an AccessKey pair is rejected by OSS; a credentials provider needs none.
+	g = packGraph("JindoRuntime", types.MountInfo{Name: "oss", MountPoint: "oss://bucket/data", OptionKeys: []string{"fs.oss.endpoint"},
+		EncryptOptions: []types.EncryptOption{{Name: "fs.oss.accessKeyId", SecretName: "oss-creds", SecretKey: "id"}}})
+	g.Secrets = []types.SecretInfo{{Name: "oss-creds", Found: true, Keys: []string{"id"}}}
+	hints := findings(diagnose.Diagnose(g), "JINDO_OSS_CONFIG_INVALID")
+	require.Len(t, hints, 1)
+	assert.Equal(t, types.SeverityCritical, hints[0].Severity)
+	assert.Equal(t, "Mount oss: fs.oss.accessKeyId without fs.oss.accessKeySecret", hints[0].Evidence.Detail)
+	g.Dataset.Mounts[0].OptionKeys = append(g.Dataset.Mounts[0].OptionKeys, "fs.oss.credentials.provider")
+	assert.Empty(t, findings(diagnose.Diagnose(g), "JINDO_OSS_CONFIG_INVALID"))
+}
+
+func TestRuntimePacks_Credentials(t *testing.T) {
+	secret := func(name, key string) types.EncryptOption {
+		return types.EncryptOption{Name: name, SecretName: "creds", SecretKey: key}
+	}
+	details := func(g *types.ResourceGraph, id string) []string {
+		var out []string
+		for _, h := range findings(diagnose.Diagnose(g), id) {
+			out = append(out, string(h.Severity)+": "+h.Evidence.Detail)
+		}
+		return out
+	}
+
+	// Alluxio reads the AccessKey pair by the under storage's scheme.
+	g := packGraph("AlluxioRuntime",
+		types.MountInfo{Name: "s3", MountPoint: "s3://bucket/data", EncryptOptions: []types.EncryptOption{secret("aws.accessKeyId", "id"), secret("aws.secretKey", "key")}},
+		types.MountInfo{Name: "oss", MountPoint: "oss://bucket/data", OptionKeys: []string{"fs.oss.accessKeyId"}},
+		types.MountInfo{Name: "hdfs", MountPoint: "hdfs://namenode:9000/data"},
+	)
+	g.Secrets = []types.SecretInfo{{Name: "creds", Found: true, Keys: []string{"id"}}}
+	assert.ElementsMatch(t, []string{
+		"Critical: Mount s3: Secret creds has no key key referenced by aws.secretKey (keys: id)",
+		"Warning: Mount oss: fs.oss.accessKeyId is a plain option",
+		"Critical: Mount oss: fs.oss.accessKeyId without fs.oss.accessKeySecret",
+	}, details(g, "ALLUXIO_UFS_CREDENTIALS_INVALID"))
+	g.Runtime.Type = "JindoRuntime"
+	assert.Empty(t, details(g, "ALLUXIO_UFS_CREDENTIALS_INVALID"))
+
+	// JuiceFS reads the bucket's pair next to the metaurl; a volume without a bucket needs none.
+	g = packGraph("JuiceFSRuntime", types.MountInfo{Name: "jfs", MountPoint: "juicefs:///",
+		EncryptOptions: []types.EncryptOption{secret("metaurl", "metaurl"), secret("access-key", "access-key")}})
+	g.Secrets = []types.SecretInfo{{Name: "creds", Found: true, Keys: []string{"metaurl", "access-key"}}}
+	assert.Equal(t, []string{"Critical: Mount jfs: access-key without secret-key"}, details(g, "JUICEFS_STORAGE_CREDENTIALS_INVALID"))
+	g.Dataset.Mounts[0].EncryptOptions = g.Dataset.Mounts[0].EncryptOptions[:1]
+	assert.Empty(t, details(g, "JUICEFS_STORAGE_CREDENTIALS_INVALID"))
+}
+
+func TestRuntimePacks_ThinProfile(t *testing.T) {
+	missing := false
+	g := packGraph("ThinRuntime")
+	g.Runtime.ProfileName, g.Runtime.ProfileFound = "nfs", &missing
+	g.Dataset.Status = "NotBound"
+
+	result := diagnose.Diagnose(g)
+	hints := findings(result, "THIN_PROFILE_MISSING")
+	require.Len(t, hints, 1)
+	assert.Equal(t, "ThinRuntimeProfile nfs not found", hints[0].Evidence.Detail)
+	assert.True(t, hints[0].RootCause)
+	require.Len(t, findings(result, "DATASET_NOT_BOUND"), 1)
+	assert.Equal(t, []string{"THIN_PROFILE_MISSING"}, findings(result, "DATASET_NOT_BOUND")[0].CausedBy)
+
+	// Unknown whether the profile exists: nothing to report.
+	g.Runtime.ProfileFound = nil
+	assert.Empty(t, findings(diagnose.Diagnose(g), "THIN_PROFILE_MISSING"))
+}
+
+func TestRuntimePacks_ThinFuseImage(t *testing.T) {
+	found := true
+	g := packGraph("ThinRuntime")
+	g.Runtime.ProfileName, g.Runtime.ProfileFound = "nfs", &found
+	g.Runtime.Fuse.Ready = 0
+
+	result := diagnose.Diagnose(g)
+	hints := findings(result, "THIN_FUSE_IMAGE_MISSING")
+	require.Len(t, hints, 1)
+	assert.Equal(t, "Neither the ThinRuntime nor ThinRuntimeProfile nfs sets spec.fuse.image", hints[0].Evidence.Detail)
+	assert.Equal(t, "ThinRuntimeProfile", hints[0].Evidence.Objects[1].Kind)
+	assert.Equal(t, []string{"THIN_FUSE_IMAGE_MISSING"}, findings(result, "FUSE_MISSING")[0].CausedBy)
+
+	// Set by the runtime or the profile; a missing profile is THIN_PROFILE_MISSING alone.
+	g.Runtime.FuseImage = "fluidcloudnative/nfs:v0.1"
+	assert.Empty(t, findings(diagnose.Diagnose(g), "THIN_FUSE_IMAGE_MISSING"))
+	g.Runtime.FuseImage, found = "", false
+	assert.Empty(t, findings(diagnose.Diagnose(g), "THIN_FUSE_IMAGE_MISSING"))
+}
@@ -0,0 +1,294 @@
+package diagnose
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// POD_UNSCHEDULABLE
+// Explains why no node fits a pending runtime pod. The scheduler's message, from the
+// PodScheduled condition or the latest FailedScheduling event, counts the nodes each
+// predicate excluded; the predicate that excluded the most nodes is reported and
+// checked against the node inventory. Escalates with the pod's age, since the
+// cluster autoscaler may still add a fitting node.
+type PodUnschedulableRule struct {
+	Escalation Escalation
+}
+
+func (r *PodUnschedulableRule) ID() string { return "POD_UNSCHEDULABLE" }
+
+func (r *PodUnschedulableRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Pod unschedulable",
+		Description: "A runtime pod is Pending because no node fits it. Reports the scheduling predicate that excluded the most nodes and checks it against the node inventory. Escalates with the pod's age.",
+		Severity:    "Critical/Warning",
+		Component:   "Runtime pods",
+		Checks:      "The PodScheduled condition or the latest FailedScheduling event of pending runtime pods, checked against node allocatable, labels, taints and cordons.",
+		Causes:      []string{"Resource requests larger than any node's allocatable", "A nodeSelector or affinity that no node matches", "Untolerated taints or cordoned nodes", "Host ports already taken"},
+		Remediation: []string{"kubectl describe pod <pod> -n <ns>", "kubectl get nodes --show-labels", "Adjust spec.<component>.nodeSelector, tolerations or resource requests"},
+		Related:     []string{"HOST_PORT_CONFLICT", "TIEREDSTORE_EXCEEDS_NODE_ALLOCATABLE"},
+	}
+}
+
+func (r *PodUnschedulableRule) Configure(params map[string]string) (Rule, error) {
+	c := *r
+	return &c, setParams(params, escalationParams(&c.Escalation, nil))
+}
+
+func (r *PodUnschedulableRule) withSeverity(sev types.SeverityLevel) Rule {
+	c := *r
+	c.Escalation.Severity = sev
+	return &c
+}
+
+func (r *PodUnschedulableRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	var hints []types.FailureHint
+	forEachPod(g, func(rc runtimeComponent, p types.PodInfo) {
+		if p.Node != "" || p.Phase == "Failed" || p.Phase == "Succeeded" {
+			return
+		}
+		msg := p.Unschedulable
+		if _, ok := parseSchedulingMessage(msg); !ok {
+			if e := latestEvent(g, "Pod", p.Name, "FailedScheduling"); e != nil {
+				msg = e.Message
+			}
+		}
+		if msg == "" {
+			return
+		}
+
+		field := componentField(rc.component)
+		format, facts := "Pending: {message}", []types.Fact{fact("message", msg)}
+		suggestion := fmt.Sprintf("No node satisfies the pod's requirements. Check nodeSelector, tolerations and resource requests in spec.%s of the %s against the nodes.", field, runtimeKind(g))
+		if sf, ok := parseSchedulingMessage(msg); ok && len(sf.predicates) > 0 {
+			top := sf.predicates[0]
+			format = "Pending: 0/{nodes} nodes are available, most excluded by: {predicate} ({excluded})"
+			facts = []types.Fact{fact("nodes", sf.total), fact("predicate", top.reason), fact("excluded", top.count)}
+			if inv := nodeInventory(g, p, top.reason); inv != "" {
+				format += ". {inventory}"
+				facts = append(facts, fact("inventory", inv))
+			}
+			suggestion = predicateSuggestion(top.reason, field, runtimeKind(g))
+		}
+
+		h := podHint(r.ID(), rc, p, format, suggestion, facts...)
+		severity, context := r.Escalation.severity(g, h.Severity, p.CreationTimestamp)
+		h.Severity, h.Context = severity, joinContext(context, nodeSummary(g.Nodes))
+		hints = append(hints, h)
+	})
+	return hints
+}
+
+// schedulingFailure is a parsed scheduler message such as
+// "0/5 nodes are available: 3 Insufficient memory, 2 node(s) were unschedulable."
+type schedulingFailure struct {
+	total      int
+	predicates []predicateCount // Most excluding first
+}
+
+type predicateCount struct {
+	count  int
+	reason string
+}
+
+var (
+	schedulingMessagePattern = regexp.MustCompile(`^0/(\d+) nodes are available: (.*)$`)
+	predicatePattern         = regexp.MustCompile(`^(\d+) (.+)$`)
+)
+
+// parseSchedulingMessage parses the scheduler's FailedScheduling message. The
+// preemption summary that newer schedulers append is ignored.
+func parseSchedulingMessage(msg string) (schedulingFailure, bool) {
+	m := schedulingMessagePattern.FindStringSubmatch(strings.TrimSpace(msg))
+	if m == nil {
+		return schedulingFailure{}, false
+	}
+	total, _ := strconv.Atoi(m[1])
+	reasons := m[2]
+	if i := strings.Index(reasons, " preemption:"); i >= 0 {
+		reasons = reasons[:i]
+	}
+
+	sf := schedulingFailure{total: total}
+	for _, part := range strings.Split(strings.TrimSuffix(strings.TrimSpace(reasons), "."), ", ") {
+		pm := predicatePattern.FindStringSubmatch(strings.TrimSpace(part))
+		if pm == nil {
+			continue
+		}
+		count, _ := strconv.Atoi(pm[1])
+		sf.predicates = append(sf.predicates, predicateCount{count: count, reason: pm[2]})
+	}
+	sort.SliceStable(sf.predicates, func(i, j int) bool {
+		return sf.predicates[i].count > sf.predicates[j].count
+	})
+	return sf, true
+}
+
+// nodeInventory checks a scheduling predicate against the node inventory, e.g. the
+// largest allocatable amount of an insufficient resource. Empty without inventory.
+func nodeInventory(g *types.ResourceGraph, p types.PodInfo, reason string) string {
+	if len(g.Nodes) == 0 {
+		return ""
+	}
+	switch {
+	case strings.HasPrefix(reason, "Insufficient "):
+		res := corev1.ResourceName(strings.TrimPrefix(reason, "Insufficient "))
+		var largest resource.Quantity
+		best := ""
+		for _, n := range g.Nodes {
+			if !n.Ready || n.Unschedulable {
+				continue
+			}
+			if q, ok := n.Allocatable[res]; ok && (best == "" || q.Cmp(largest) > 0) {
+				largest, best = q, n.Name
+			}
+		}
+		out := ""
+		if req, ok := p.Requests[res]; ok {
+			out = fmt.Sprintf("Pod requests %s %s", req.String(), res)
+		}
+		if best != "" {
+			out = joinSentence(out, fmt.Sprintf("largest node allocatable: %s (%s)", largest.String(), best))
+		}
+		return out
+	case strings.Contains(reason, "node affinity/selector"):
+		if len(p.NodeSelector) == 0 {
+			return ""
+		}
+		matching := 0
+		for _, n := range g.Nodes {
+			if labelsMatch(n.Labels, p.NodeSelector) {
+				matching++
+			}
+		}
+		return fmt.Sprintf("%d/%d nodes match nodeSelector %s", matching, len(g.Nodes), formatSelector(p.NodeSelector))
+	case strings.Contains(reason, "taint"):
+		return taintSummary(g.Nodes)
+	case strings.Contains(reason, "were unschedulable"):
+		var cordoned []string
+		for _, n := range g.Nodes {
+			if n.Unschedulable {
+				cordoned = append(cordoned, n.Name)
+			}
+		}
+		if len(cordoned) == 0 {
+			return ""
+		}
+		return "Cordoned: " + strings.Join(cordoned, ", ")
+	}
+	return ""
+}
+
+// predicateSuggestion gives the remediation for the predicate that excluded the most nodes.
+func predicateSuggestion(reason, field, runtimeKind string) string {
+	switch {
+	case strings.HasPrefix(reason, "Insufficient "):
+		return fmt.Sprintf("Lower spec.%s.resources.requests on the %s, or add nodes with enough capacity.", field, runtimeKind)
+	case strings.Contains(reason, "node affinity/selector"):
+		return fmt.Sprintf("Label nodes to match, or fix spec.%s.nodeSelector on the %s and spec.nodeAffinity on the Dataset.", field, runtimeKind)
+	case strings.Contains(reason, "taint"):
+		return fmt.Sprintf("Add tolerations for the node taints to the %s and Dataset, or schedule onto untainted nodes.", runtimeKind)
+	case strings.Contains(reason, "free ports"):
+		return fmt.Sprintf("The %s pods use the host network; another pod on the candidate nodes already holds the port. Change the ports in the %s spec or move the conflicting runtime.", field, runtimeKind)
+	case strings.Contains(reason, "were unschedulable"):
+		return "The candidate nodes are cordoned. Uncordon them once maintenance is done: kubectl uncordon <node>"
+	case strings.Contains(reason, "volume node affinity conflict"):
+		return "The pod's volumes are bound to PersistentVolumes in another zone than the candidate nodes. Check the cache volume's topology."
+	}
+	return fmt.Sprintf("No node satisfies the pod's requirements. Check nodeSelector, tolerations and resource requests in spec.%s of the %s against the nodes.", field, runtimeKind)
+}
+
+// latestEvent returns the most recent event with the given reason about an object.
+func latestEvent(g *types.ResourceGraph, kind, name, reason string) *types.EventInfo {
+	var latest *types.EventInfo
+	for i, e := range g.Events {
+		if e.Kind == kind && e.Name == name && e.Reason == reason {
+			latest = &g.Events[i] // Events are oldest first
+		}
+	}
+	return latest
+}
+
+// nodeSummary describes the node inventory, e.g. "Nodes: 5 (4 ready, 1 cordoned).".
+func nodeSummary(nodes []types.NodeInfo) string {
+	if len(nodes) == 0 {
+		return ""
+	}
+	ready, cordoned := 0, 0
+	for _, n := range nodes {
+		if n.Ready {
+			ready++
+		}
+		if n.Unschedulable {
+			cordoned++
+		}
+	}
+	return fmt.Sprintf("Nodes: %d (%d ready, %d cordoned).", len(nodes), ready, cordoned)
+}
+
+// taintSummary lists the distinct scheduling taints of the nodes with their node counts.
+func taintSummary(nodes []types.NodeInfo) string {
+	counts := make(map[string]int)
+	for _, n := range nodes {
+		for _, t := range n.Taints {
+			if t.Effect == corev1.TaintEffectPreferNoSchedule {
+				continue
+			}
+			counts[t.ToString()]++
+		}
+	}
+	if len(counts) == 0 {
+		return ""
+	}
+	taints := make([]string, 0, len(counts))
+	for t := range counts {
+		taints = append(taints, t)
+	}
+	sort.Strings(taints)
+	for i, t := range taints {
+		taints[i] = fmt.Sprintf("%s on %d node(s)", t, counts[t])
+	}
+	return "Taints: " + strings.Join(taints, "; ")
+}
+
+func labelsMatch(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func formatSelector(selector map[string]string) string {
+	pairs := make([]string, 0, len(selector))
+	for k, v := range selector {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func joinSentence(a, b string) string {
+	if a == "" {
+		return strings.ToUpper(b[:1]) + b[1:]
+	}
+	return a + "; " + b
+}
+
+func joinContext(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, " ")
+}
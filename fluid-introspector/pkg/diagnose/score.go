@@ -0,0 +1,146 @@
+package diagnose
+
+import (
+	"strings"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+)
+
+// Scoring model. Each component starts at 100 and loses a penalty per active Warning or
+// Critical finding attributed to it, down to 0. Info findings, e.g. of a condition still
+// in its grace period, are expected states and do not count. The Dataset's score is the weighted average of its
+// component scores, capped so that severity shows through the average: a Critical
+// finding caps it at 49, a Warning at 89. Silenced findings do not count; a graph
+// that failed validation scores 0.
+
+// healthComponents lists the scored components and their weights, summing to 100.
+var healthComponents = []struct {
+	name   string
+	weight int
+}{
+	{"dataset", 20},
+	{"master", 25},
+	{"worker", 20},
+	{"fuse", 15},
+	{"storage", 10},
+	{"operations", 10},
+}
+
+const (
+	criticalPenalty = 60
+	warningPenalty  = 20
+
+	criticalScoreCap = 49
+	warningScoreCap  = 89
+)
+
+// scoreResult sets the score and component health of the result from its active findings.
+func scoreResult(result *types.DiagnosticResult) {
+	penalty := make(map[string]int)
+	findings := make(map[string]int)
+	worst := make(map[string]types.SeverityLevel)
+	for _, h := range result.FailureHints {
+		if h.Severity == types.SeverityInfo {
+			continue
+		}
+		for _, c := range healthComponentsOf(result.ResourceGraph, h) {
+			penalty[c] += severityPenalty(h.Severity)
+			findings[c]++
+			if severityRank(h.Severity) > severityRank(worst[c]) {
+				worst[c] = h.Severity
+			}
+		}
+	}
+
+	total, top := 0, types.SeverityLevel("")
+	result.Components = make([]types.ComponentHealth, 0, len(healthComponents))
+	for _, hc := range healthComponents {
+		score := 100 - penalty[hc.name]
+		if score < 0 {
+			score = 0
+		}
+		status := types.HealthHealthy
+		switch worst[hc.name] {
+		case types.SeverityCritical:
+			status = types.HealthUnhealthy
+		case types.SeverityWarning:
+			status = types.HealthDegraded
+		}
+		if severityRank(worst[hc.name]) > severityRank(top) {
+			top = worst[hc.name]
+		}
+		result.Components = append(result.Components, types.ComponentHealth{Name: hc.name, Status: status, Score: score, Findings: findings[hc.name]})
+		total += score * hc.weight
+	}
+
+	// Weights sum to 100; round to the nearest point.
+	result.Score = (total + 50) / 100
+	switch {
+	case hasFinding(result.FailureHints, InvalidGraphID):
+		result.Score = 0
+	case top == types.SeverityCritical && result.Score > criticalScoreCap:
+		result.Score = criticalScoreCap
+	case top == types.SeverityWarning && result.Score > warningScoreCap:
+		result.Score = warningScoreCap
+	}
+}
+
+// healthComponentsOf maps a finding to the scored components by its Component.
+// Runtime-wide findings, such as a missing Runtime, count against master, worker
+// and fuse. Findings of other components about a runtime pod, e.g. of a custom rule,
+// count against the pod's component; the rest, such as findings about the Fluid
+// controllers or the engine, against operations.
+func healthComponentsOf(g *types.ResourceGraph, h types.FailureHint) []string {
+	switch component := h.Component; {
+	case component == "Dataset":
+		return []string{"dataset"}
+	case component == "Runtime/Master":
+		return []string{"master"}
+	case component == "Runtime/Worker":
+		return []string{"worker"}
+	case component == "Runtime/Fuse":
+		return []string{"fuse"}
+	case component == "Runtime":
+		return []string{"master", "worker", "fuse"}
+	case strings.HasPrefix(component, "Infrastructure"):
+		return []string{"storage"}
+	case h.Evidence.Kind == "Pod":
+		if c, ok := podComponent(g, h.Evidence.Name); ok {
+			return []string{c}
+		}
+	}
+	return []string{"operations"}
+}
+
+// podComponent returns the scored component of a runtime pod: master, worker or fuse.
+func podComponent(g *types.ResourceGraph, name string) (string, bool) {
+	if g == nil || g.Runtime == nil {
+		return "", false
+	}
+	for _, rc := range runtimeComponents(g.Runtime) {
+		for _, p := range rc.info.Pods {
+			if p.Name == name {
+				return componentField(rc.component), true
+			}
+		}
+	}
+	return "", false
+}
+
+func severityPenalty(s types.SeverityLevel) int {
+	switch s {
+	case types.SeverityCritical:
+		return criticalPenalty
+	default:
+		return warningPenalty
+	}
+}
+
+func hasFinding(hints []types.FailureHint, id string) bool {
+	for _, h := range hints {
+		if h.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package diagnose
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+)
+
+// THIN_PROFILE_MISSING
+// A ThinRuntime takes its fuse image and file system type from the cluster-scoped
+// ThinRuntimeProfile named by spec.profileName; without it no fuse can be built.
+type ThinProfileMissingRule struct{}
+
+func (r *ThinProfileMissingRule) ID() string { return "THIN_PROFILE_MISSING" }
+
+func (r *ThinProfileMissingRule) RuntimeTypes() []string { return []string{"ThinRuntime"} }
+
+func (r *ThinProfileMissingRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "ThinRuntimeProfile missing",
+		Description: "ThinRuntime only: spec.profileName is empty or names a ThinRuntimeProfile that does not exist.",
+		Severity:    "Critical",
+		Component:   "Runtime",
+		Checks:      "spec.profileName of the ThinRuntime and whether the cluster-scoped ThinRuntimeProfile exists. Not reported when profiles cannot be read.",
+		Causes:      []string{"The profile was not installed before the ThinRuntime", "A typo in spec.profileName"},
+		Remediation: []string{"kubectl get thinruntimeprofiles", "Create the profile or fix spec.profileName"},
+		Related:     []string{"DATASET_NOT_BOUND", "FUSE_MISSING"},
+	}
+}
+
+func (r *ThinProfileMissingRule) Inputs(g *types.ResourceGraph) []types.Fact {
+	if g.Runtime == nil {
+		return []types.Fact{{Key: "runtime", Value: "none"}}
+	}
+	found := "unknown"
+	if g.Runtime.ProfileFound != nil {
+		found = strconv.FormatBool(*g.Runtime.ProfileFound)
+	}
+	return []types.Fact{{Key: "profileName", Value: orNone(g.Runtime.ProfileName)}, {Key: "profileFound", Value: found}}
+}
+
+func (r *ThinProfileMissingRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	if g.Runtime == nil {
+		return nil
+	}
+	var format string
+	switch {
+	case g.Runtime.ProfileName == "":
+		format = "spec.profileName is not set"
+	case g.Runtime.ProfileFound != nil && !*g.Runtime.ProfileFound:
+		format = "ThinRuntimeProfile {profileName} not found"
+	default:
+		return nil
+	}
+	return []types.FailureHint{{
+		ID:         r.ID(),
+		Severity:   types.SeverityCritical,
+		Component:  "Runtime",
+		Evidence:   factEvidence("ThinRuntime", g.Runtime.Name, format, fact("profileName", g.Runtime.ProfileName)),
+		Suggestion: "Create the ThinRuntimeProfile for the file system, or set spec.profileName to an existing one.",
+		Remediation: []types.RemediationStep{
+			{Description: "List the installed profiles", Command: "kubectl get thinruntimeprofiles"},
+			{Description: "Read the ThinRuntime's spec and status", Command: fmt.Sprintf("kubectl get thinruntime %s%s -o yaml", g.Runtime.Name, namespaceFlag(g))},
+		},
+	}}
+}
+
+// THIN_FUSE_IMAGE_MISSING
+// The fuse of a ThinRuntime runs the image of spec.fuse.image, or else the one its
+// ThinRuntimeProfile declares; a profile without one only works for runtimes setting it.
+type ThinFuseImageMissingRule struct{}
+
+func (r *ThinFuseImageMissingRule) ID() string { return "THIN_FUSE_IMAGE_MISSING" }
+
+func (r *ThinFuseImageMissingRule) RuntimeTypes() []string { return []string{"ThinRuntime"} }
+
+func (r *ThinFuseImageMissingRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "ThinRuntime fuse image missing",
+		Description: "ThinRuntime only: neither the ThinRuntime nor its ThinRuntimeProfile sets spec.fuse.image, so no fuse can start.",
+		Severity:    "Critical",
+		Component:   "Runtime/Fuse",
+		Checks:      "spec.fuse.image of the ThinRuntime and of the ThinRuntimeProfile it names. Not reported when the profile is missing or cannot be read.",
+		Causes:      []string{"A profile written for runtimes that set their own fuse image", "spec.fuse.image left out of the profile"},
+		Remediation: []string{"kubectl get thinruntimeprofile <profile> -o yaml", "Set spec.fuse.image in the profile, or in the ThinRuntime"},
+		Related:     []string{"THIN_PROFILE_MISSING", "FUSE_MISSING"},
+	}
+}
+
+func (r *ThinFuseImageMissingRule) Inputs(g *types.ResourceGraph) []types.Fact {
+	if g.Runtime == nil {
+		return []types.Fact{{Key: "runtime", Value: "none"}}
+	}
+	return []types.Fact{{Key: "profileName", Value: orNone(g.Runtime.ProfileName)}, {Key: "fuseImage", Value: orNone(g.Runtime.FuseImage)}}
+}
+
+func (r *ThinFuseImageMissingRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	if g.Runtime == nil || g.Runtime.ProfileFound == nil || !*g.Runtime.ProfileFound || g.Runtime.FuseImage != "" {
+		return nil
+	}
+	hint := types.FailureHint{
+		ID:         r.ID(),
+		Severity:   types.SeverityCritical,
+		Component:  "Runtime/Fuse",
+		Evidence:   factEvidence("ThinRuntime", g.Runtime.Name, "Neither the ThinRuntime nor ThinRuntimeProfile {profileName} sets spec.fuse.image", fact("profileName", g.Runtime.ProfileName)),
+		Suggestion: "Set spec.fuse.image to the file system's fuse image in the ThinRuntimeProfile, for every runtime using it, or in the ThinRuntime.",
+		Remediation: []types.RemediationStep{
+			{Description: "Read the ThinRuntimeProfile's fuse spec", Command: fmt.Sprintf("kubectl get thinruntimeprofile %s -o yaml", g.Runtime.ProfileName)},
+		},
+	}
+	hint.Evidence.Objects = []types.ObjectRef{ref("ThinRuntime", g.Runtime.Name), ref("ThinRuntimeProfile", g.Runtime.ProfileName)}
+	return []types.FailureHint{hint}
+}
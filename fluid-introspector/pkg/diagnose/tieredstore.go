@@ -0,0 +1,280 @@
+package diagnose
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Tiered store rules check the cache levels of spec.tieredstore against the
+// resources the workers actually get. Every worker caches up to each level's quota.
+
+// TIEREDSTORE_MEM_EXCEEDS_LIMIT
+// A MEM level lives in tmpfs, which is charged to the worker's memory cgroup: a
+// quota above the worker's memory limit gets the worker OOMKilled once the cache fills.
+type TieredStoreMemExceedsLimitRule struct{}
+
+func (r *TieredStoreMemExceedsLimitRule) ID() string { return "TIEREDSTORE_MEM_EXCEEDS_LIMIT" }
+
+func (r *TieredStoreMemExceedsLimitRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "MEM cache exceeds worker memory limit",
+		Description: "The MEM tiered store quota exceeds the worker's memory limit; the worker is OOMKilled once the cache fills.",
+		Severity:    "Warning",
+		Component:   "Runtime/Worker",
+		Checks:      "The summed MEM quota of spec.tieredstore against the memory limits of a worker pod's app containers.",
+		Causes:      []string{"A MEM quota sized for the node rather than the container"},
+		Remediation: []string{"Lower the MEM quota below spec.worker.resources.limits.memory", "Raise the worker memory limit"},
+		Related:     []string{"OOM_KILLED", "TIEREDSTORE_EXCEEDS_NODE_ALLOCATABLE"},
+	}
+}
+
+func (r *TieredStoreMemExceedsLimitRule) Inputs(g *types.ResourceGraph) []types.Fact {
+	inputs := tieredStoreInputs(g)
+	if g.Runtime == nil || g.Runtime.Worker == nil {
+		return inputs
+	}
+	limit := "unknown"
+	for _, p := range g.Runtime.Worker.Pods {
+		if l, ok := podMemoryLimit(p); ok {
+			limit = l.String()
+			break
+		}
+	}
+	return append(inputs, types.Fact{Key: "workerMemoryLimit", Value: limit})
+}
+
+func (r *TieredStoreMemExceedsLimitRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	if g.Runtime == nil || g.Runtime.Worker == nil {
+		return nil
+	}
+	quota := mediumQuota(g.Runtime.TieredStore, "MEM")
+	if quota.IsZero() {
+		return nil
+	}
+
+	// Workers share one template; the first pod with a known limit stands for all.
+	for _, p := range g.Runtime.Worker.Pods {
+		limit, ok := podMemoryLimit(p)
+		if !ok || quota.Cmp(limit) <= 0 {
+			continue
+		}
+		return []types.FailureHint{{
+			ID:        r.ID(),
+			Severity:  types.SeverityWarning,
+			Component: "Runtime/Worker",
+			Evidence: factEvidence(g.Runtime.Type, g.Runtime.Name, "MEM quota: {quota}, Worker memory limit: {memoryLimit} (pod {pod})",
+				fact("quota", quota.String()), fact("memoryLimit", limit.String()), fact("pod", p.Name)),
+			Suggestion: fmt.Sprintf("Lower the MEM quota in spec.tieredstore.levels below spec.worker.resources.limits.memory of the %s, leaving room for the worker process itself, or raise the limit.", runtimeKind(g)),
+			Remediation: []types.RemediationStep{
+				{Description: fmt.Sprintf("Read the tiered store and worker resources of the %s", runtimeKind(g)), Command: fmt.Sprintf("kubectl get %s %s%s -o yaml", strings.ToLower(runtimeKind(g)), g.Runtime.Name, namespaceFlag(g))},
+				memoryLimitPatch(g, "Runtime/Worker", workerLimitFor(quota), "Raise the worker memory limit to the MEM quota plus 1Gi for the worker process"),
+			},
+		}}
+	}
+	return nil
+}
+
+// workerLimitFor returns a worker memory limit holding a MEM cache of quota.
+func workerLimitFor(quota resource.Quantity) resource.Quantity {
+	limit := quota.DeepCopy()
+	limit.Add(resource.MustParse("1Gi"))
+	return limit
+}
+
+// TIEREDSTORE_EXCEEDS_NODE_ALLOCATABLE
+// Reports each node hosting a worker whose allocatable memory (for MEM levels) or
+// ephemeral storage (for SSD/HDD levels) is smaller than the cache quota. Disk levels
+// on dedicated volumes are not limited by ephemeral storage; treat those as a hint.
+type TieredStoreExceedsNodeAllocatableRule struct{}
+
+func (r *TieredStoreExceedsNodeAllocatableRule) ID() string {
+	return "TIEREDSTORE_EXCEEDS_NODE_ALLOCATABLE"
+}
+
+func (r *TieredStoreExceedsNodeAllocatableRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Cache exceeds node allocatable",
+		Description: "A tiered store quota exceeds the allocatable memory (MEM) or ephemeral storage (SSD/HDD) of a node running a worker.",
+		Severity:    "Warning",
+		Component:   "Runtime/Worker",
+		Checks:      "MEM quota against node allocatable memory and SSD/HDD quota against allocatable ephemeral storage, for nodes running workers.",
+		Causes:      []string{"Quotas sized for larger nodes than the workers land on"},
+		Remediation: []string{"Lower the quota in spec.tieredstore.levels", "Pin workers to larger nodes with spec.worker.nodeSelector"},
+		Related:     []string{"TIEREDSTORE_MEM_EXCEEDS_LIMIT", "TIEREDSTORE_DISK_PRESSURE"},
+	}
+}
+
+func (r *TieredStoreExceedsNodeAllocatableRule) Inputs(g *types.ResourceGraph) []types.Fact {
+	return tieredStoreInputs(g)
+}
+
+func (r *TieredStoreExceedsNodeAllocatableRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	if g.Runtime == nil || g.Runtime.Worker == nil || len(g.Runtime.TieredStore) == 0 {
+		return nil
+	}
+	checks := []struct {
+		medium   string
+		resource corev1.ResourceName
+		quota    resource.Quantity
+	}{
+		{"MEM", corev1.ResourceMemory, mediumQuota(g.Runtime.TieredStore, "MEM")},
+		{"SSD/HDD", corev1.ResourceEphemeralStorage, diskQuota(g.Runtime.TieredStore)},
+	}
+
+	var hints []types.FailureHint
+	for _, n := range workerNodes(g) {
+		for _, c := range checks {
+			allocatable, ok := n.Allocatable[c.resource]
+			if c.quota.IsZero() || !ok || c.quota.Cmp(allocatable) <= 0 {
+				continue
+			}
+			hints = append(hints, types.FailureHint{
+				ID:        r.ID(),
+				Severity:  types.SeverityWarning,
+				Component: "Runtime/Worker",
+				Evidence: factEvidence("Node", n.Name, fmt.Sprintf("{medium} quota: {quota}, Allocatable %s: {allocatable}", c.resource),
+					fact("medium", c.medium), fact("quota", c.quota.String()), fact("allocatable", allocatable.String())),
+				Suggestion: fmt.Sprintf("Lower the %s quota in spec.tieredstore.levels of the %s, or pin workers to larger nodes with spec.worker.nodeSelector.", c.medium, runtimeKind(g)),
+			})
+		}
+	}
+	return hints
+}
+
+// TIEREDSTORE_DISK_PRESSURE
+// Reports each node hosting a worker with disk cache levels while the node reports
+// DiskPressure: the kubelet evicts pods and the cache cannot grow.
+type TieredStoreDiskPressureRule struct{}
+
+func (r *TieredStoreDiskPressureRule) ID() string { return "TIEREDSTORE_DISK_PRESSURE" }
+
+func (r *TieredStoreDiskPressureRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Disk pressure on a cache node",
+		Description: "A node running a worker with disk cache levels reports DiskPressure.",
+		Severity:    "Warning",
+		Component:   "Runtime/Worker",
+		Checks:      "The DiskPressure condition of nodes running workers, when the tiered store has SSD or HDD levels.",
+		Causes:      []string{"Disk cache quotas larger than the free disk space", "Other workloads filling the node's disk"},
+		Remediation: []string{"Free disk space on the node", "Lower the SSD/HDD quota in spec.tieredstore.levels"},
+		Related:     []string{"POD_EVICTED", "TIEREDSTORE_EXCEEDS_NODE_ALLOCATABLE"},
+	}
+}
+
+func (r *TieredStoreDiskPressureRule) Inputs(g *types.ResourceGraph) []types.Fact {
+	return tieredStoreInputs(g)
+}
+
+func (r *TieredStoreDiskPressureRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	if g.Runtime == nil || g.Runtime.Worker == nil {
+		return nil
+	}
+	var paths []string
+	for _, l := range g.Runtime.TieredStore {
+		if l.MediumType != "MEM" {
+			paths = append(paths, l.Paths...)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	var hints []types.FailureHint
+	for _, n := range workerNodes(g) {
+		if !hasPressure(n, string(corev1.NodeDiskPressure)) {
+			continue
+		}
+		hints = append(hints, types.FailureHint{
+			ID:         r.ID(),
+			Severity:   types.SeverityWarning,
+			Component:  "Runtime/Worker",
+			Evidence:   factEvidence("Node", n.Name, "DiskPressure with cache paths: {paths}", fact("paths", strings.Join(paths, ", "))),
+			Suggestion: "Free disk space on the node or lower the SSD/HDD quota in spec.tieredstore.levels; under DiskPressure the kubelet evicts pods, workers included.",
+		})
+	}
+	return hints
+}
+
+// tieredStoreInputs reports the cache quotas and how many inventoried nodes run workers.
+func tieredStoreInputs(g *types.ResourceGraph) []types.Fact {
+	if g.Runtime == nil || g.Runtime.Worker == nil {
+		return []types.Fact{{Key: "worker", Value: "none"}}
+	}
+	mem, disk := mediumQuota(g.Runtime.TieredStore, "MEM"), diskQuota(g.Runtime.TieredStore)
+	return []types.Fact{
+		{Key: "memQuota", Value: mem.String()},
+		{Key: "diskQuota", Value: disk.String()},
+		{Key: "workerNodes", Value: strconv.Itoa(len(workerNodes(g)))},
+	}
+}
+
+// mediumQuota sums the quotas of the levels of one medium type.
+func mediumQuota(levels []types.TieredStoreLevel, medium string) resource.Quantity {
+	var total resource.Quantity
+	for _, l := range levels {
+		if strings.EqualFold(l.MediumType, medium) {
+			total.Add(l.Quota)
+		}
+	}
+	return total
+}
+
+// diskQuota sums the quotas of the SSD and HDD levels.
+func diskQuota(levels []types.TieredStoreLevel) resource.Quantity {
+	total := mediumQuota(levels, "SSD")
+	total.Add(mediumQuota(levels, "HDD"))
+	return total
+}
+
+// podMemoryLimit sums the memory limits of a pod's app containers. It reports false
+// if any app container is unlimited.
+func podMemoryLimit(p types.PodInfo) (resource.Quantity, bool) {
+	var total resource.Quantity
+	found := false
+	for _, c := range p.Containers {
+		if c.Init {
+			continue
+		}
+		if c.MemoryLimit == "" {
+			return resource.Quantity{}, false
+		}
+		q, err := resource.ParseQuantity(c.MemoryLimit)
+		if err != nil {
+			return resource.Quantity{}, false
+		}
+		total.Add(q)
+		found = true
+	}
+	return total, found
+}
+
+// workerNodes returns the inventory of the nodes running worker pods, in inventory order.
+func workerNodes(g *types.ResourceGraph) []types.NodeInfo {
+	onNode := make(map[string]bool)
+	for _, p := range g.Runtime.Worker.Pods {
+		if p.Node != "" {
+			onNode[p.Node] = true
+		}
+	}
+	var nodes []types.NodeInfo
+	for _, n := range g.Nodes {
+		if onNode[n.Name] {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+func hasPressure(n types.NodeInfo, condition string) bool {
+	for _, p := range n.Pressures {
+		if p == condition {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package diagnose
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+)
+
+// A trace explains a run rule by rule: the graph values each rule reads, and why it
+// fired, passed or was skipped. Traced runs (see WithTrace) return it in
+// DiagnosticResult.Trace, in the order of the rule set.
+
+// Explainer is implemented by rules that read more of the graph than the fields of
+// their component, e.g. the tiered store. Inputs reports those values as observed;
+// rules without it are traced with the state of their component.
+type Explainer interface {
+	Rule
+	Inputs(g *types.ResourceGraph) []types.Fact
+}
+
+// maxTraceEvidence bounds the findings a fired rule's reason quotes.
+const maxTraceEvidence = 3
+
+// ruleInputs reports the graph values the rule reads, or nothing if the graph is
+// invalid or the rule fails to read it.
+func ruleInputs(rule Rule, g *types.ResourceGraph) (inputs []types.Fact) {
+	if len(validateGraph(g)) > 0 {
+		return nil
+	}
+	defer func() {
+		if recover() != nil {
+			inputs = nil
+		}
+	}()
+	if e, ok := rule.(Explainer); ok {
+		return e.Inputs(g)
+	}
+	return componentInputs(g, Describe(rule).Component)
+}
+
+// componentInputs reports the state of a rule component, e.g. the ready replicas of
+// the workers for "Runtime/Worker". Rules of pods and of several components see the
+// pods of the runtime.
+func componentInputs(g *types.ResourceGraph, component string) []types.Fact {
+	switch component {
+	case "Dataset":
+		return []types.Fact{
+			{Key: "status", Value: orNone(g.Dataset.Status)},
+			{Key: "phase", Value: orNone(g.Dataset.Phase)},
+			{Key: "mounts", Value: strconv.Itoa(len(g.Dataset.Mounts))},
+		}
+	case "Runtime":
+		if g.Runtime == nil {
+			return []types.Fact{{Key: "runtime", Value: "none"}}
+		}
+		return []types.Fact{{Key: "runtime", Value: runtimeKind(g)}, {Key: "phase", Value: orNone(g.Runtime.Phase)}}
+	case "Runtime/Master", "Runtime/Worker", "Runtime/Fuse":
+		field := componentField(component)
+		if g.Runtime == nil {
+			return []types.Fact{{Key: "runtime", Value: "none"}}
+		}
+		c := map[string]*types.ComponentInfo{"master": g.Runtime.Master, "worker": g.Runtime.Worker, "fuse": g.Runtime.Fuse}[field]
+		if c == nil {
+			return []types.Fact{{Key: field, Value: "none"}}
+		}
+		restarts := int32(0)
+		for _, p := range c.Pods {
+			restarts += p.Restarts
+		}
+		return []types.Fact{
+			{Key: "readyReplicas", Value: strconv.Itoa(int(c.Ready))},
+			{Key: "replicas", Value: strconv.Itoa(int(c.Replicas))},
+			{Key: "pods", Value: strconv.Itoa(len(c.Pods))},
+			{Key: "restarts", Value: strconv.Itoa(int(restarts))},
+		}
+	case "Infrastructure/PVC":
+		if g.Infrastructure == nil || g.Infrastructure.PVC == nil {
+			return []types.Fact{{Key: "pvc", Value: "none"}}
+		}
+		return []types.Fact{{Key: "pvc", Value: g.Infrastructure.PVC.Name}, {Key: "status", Value: orNone(g.Infrastructure.PVC.Status)}}
+	default:
+		pods, unready := 0, 0
+		forEachPod(g, func(_ runtimeComponent, p types.PodInfo) {
+			pods++
+			if !p.Ready {
+				unready++
+			}
+		})
+		return []types.Fact{
+			{Key: "runtimePods", Value: strconv.Itoa(pods)},
+			{Key: "unreadyPods", Value: strconv.Itoa(unready)},
+			{Key: "nodes", Value: strconv.Itoa(len(g.Nodes))},
+		}
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// traceRule explains the outcome of an evaluated rule from the findings it reported.
+func traceRule(rule Rule, g *types.ResourceGraph, hints []types.FailureHint, fleet []*types.ResourceGraph, logs map[string]string) types.RuleTrace {
+	t := types.RuleTrace{RuleID: rule.ID(), Inputs: ruleInputs(rule, g)}
+	if len(hints) == 1 && hints[0].ID == RuleErrorID && rule.ID() != RuleErrorID {
+		t.Outcome, t.Reason = types.TraceError, hints[0].Evidence.Detail
+		return t
+	}
+	if len(hints) == 0 {
+		if isLogRule(rule) && logs == nil {
+			t.Outcome, t.Reason = types.TraceSkipped, "No pod logs were sampled (--logs)"
+			return t
+		}
+		if isClusterRule(rule) && fleet == nil {
+			t.Outcome, t.Reason = types.TraceSkipped, "Fleet only: compares Datasets, see fluidctl inspect datasets"
+			return t
+		}
+		t.Outcome, t.Reason = types.TracePassed, "No finding"
+		if checks := Describe(rule).Checks; checks != "" {
+			t.Reason = "Checked: " + checks
+		}
+		return t
+	}
+
+	t.Outcome, t.Findings = types.TraceFired, len(hints)
+	var quoted []string
+	for _, h := range hints {
+		if len(quoted) == maxTraceEvidence {
+			break
+		}
+		quoted = append(quoted, evidenceSummary(h))
+	}
+	t.Reason = strings.Join(quoted, "; ")
+	if more := len(hints) - len(quoted); more > 0 {
+		t.Reason += fmt.Sprintf("; and %d more", more)
+	}
+	return t
+}
+
+// evidenceSummary renders the evidence of a finding as "Kind/name: detail", naming
+// the component of findings without evidence.
+func evidenceSummary(h types.FailureHint) string {
+	ev := h.Evidence
+	s := ev.Kind
+	if s == "" {
+		s = h.Component
+	}
+	if ev.Name != "" {
+		s += "/" + ev.Name
+	}
+	if ev.Detail != "" {
+		s += ": " + ev.Detail
+	}
+	return s
+}
+
+// skippedTrace explains a rule that was not evaluated. Its only inputs are those
+// that decided to skip it.
+func skippedTrace(rule Rule, reason string, inputs ...types.Fact) types.RuleTrace {
+	return types.RuleTrace{RuleID: rule.ID(), Outcome: types.TraceSkipped, Reason: reason, Inputs: inputs}
+}
+
+// notApplicableTrace explains why a runtime pack rule does not apply to the graph.
+func notApplicableTrace(rule Rule, g *types.ResourceGraph) types.RuleTrace {
+	runtimes := strings.Join(Describe(rule).Runtimes, ", ")
+	if g.Runtime == nil {
+		return skippedTrace(rule, fmt.Sprintf("Applies to %s only; the Dataset has no runtime", runtimes), types.Fact{Key: "runtime", Value: "none"})
+	}
+	return skippedTrace(rule, fmt.Sprintf("Applies to %s only; the runtime is %s", runtimes, runtimeKind(g)), types.Fact{Key: "runtime", Value: runtimeKind(g)})
+}
+
+func isClusterRule(rule Rule) bool {
+	_, ok := rule.(ClusterRule)
+	return ok
+}
+
+func isLogRule(rule Rule) bool {
+	_, ok := rule.(LogRule)
+	return ok
+}
+
+// countSilenced records in the trace how many findings of each rule were silenced.
+func countSilenced(trace []types.RuleTrace, silenced []types.SilencedHint) {
+	for _, s := range silenced {
+		for i := range trace {
+			if trace[i].RuleID == s.ID {
+				trace[i].Silenced++
+			}
+		}
+	}
+}
+
+// traceDisabled adds the rules the profile disabled to a trace of the profiled set,
+// keeping the order of the full set.
+func traceDisabled(trace []types.RuleTrace, full RuleSet, profile *Profile) []types.RuleTrace {
+	byID := make(map[string]types.RuleTrace, len(trace))
+	for _, t := range trace {
+		byID[t.RuleID] = t
+	}
+	out := make([]types.RuleTrace, 0, len(full))
+	for _, r := range full {
+		if t, ok := byID[r.ID()]; ok {
+			out = append(out, t)
+			continue
+		}
+		out = append(out, skippedTrace(r, "Disabled by profile "+profile.Name))
+	}
+	return out
+}
@@ -0,0 +1,71 @@
+package diagnose
+
+import (
+	"fmt"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+)
+
+// Engine findings report problems of the diagnosis itself rather than of the Dataset.
+const (
+	// ENGINE_INVALID_GRAPH: the graph lacks what every rule relies on; no rule ran.
+	InvalidGraphID = "ENGINE_INVALID_GRAPH"
+	// ENGINE_RULE_ERROR: a rule panicked or failed; its findings are missing from the result.
+	RuleErrorID = "ENGINE_RULE_ERROR"
+)
+
+// validateGraph checks the invariants rules may rely on: a graph describes one
+// Dataset. It returns the problems found, if any.
+func validateGraph(g *types.ResourceGraph) []string {
+	var problems []string
+	if g.Dataset == nil {
+		problems = append(problems, "Dataset is missing from graph")
+	}
+	return problems
+}
+
+// invalidGraphHint reports a graph that failed validation.
+func invalidGraphHint(problem string) types.FailureHint {
+	return types.FailureHint{
+		ID:         InvalidGraphID,
+		Severity:   types.SeverityCritical,
+		Component:  "Engine",
+		Evidence:   types.Evidence{Kind: "ResourceGraph", Detail: problem},
+		Suggestion: "The graph was not fully mapped, so no rule was evaluated. Check that the Dataset exists and is readable, or fix the program building the graph.",
+	}
+}
+
+// ruleErrorHint reports a rule that could not evaluate the graph.
+func ruleErrorHint(id, detail, suggestion string) types.FailureHint {
+	return types.FailureHint{
+		ID:         RuleErrorID,
+		Severity:   types.SeverityWarning,
+		Component:  "Engine",
+		Evidence:   types.Evidence{Kind: "Rule", Name: id, Detail: detail},
+		Suggestion: suggestion,
+	}
+}
+
+// evaluateRule runs one rule, with cluster rules seeing the fleet, log rules the
+// sampled logs and document rules the graph document of the run. A panic is
+// recovered and reported as an ENGINE_RULE_ERROR finding in place of the rule's
+// findings.
+func evaluateRule(rule Rule, g *types.ResourceGraph, fleet []*types.ResourceGraph, logs map[string]string, doc *runDocument) (hints []types.FailureHint) {
+	defer func() {
+		if p := recover(); p != nil {
+			hints = []types.FailureHint{ruleErrorHint(rule.ID(), fmt.Sprintf("Rule panicked: %v", p),
+				"The rule could not evaluate this graph, so its findings are missing. Report the panic together with the graph (-o json) to the rule's maintainers.")}
+		}
+	}()
+
+	if dr, ok := rule.(documentRule); ok {
+		return dr.evaluateDocument(doc.get(g))
+	}
+	if lr, ok := rule.(LogRule); ok && logs != nil {
+		return lr.EvaluateLogs(g, logs)
+	}
+	if cr, ok := rule.(ClusterRule); ok && fleet != nil {
+		return cr.EvaluateCluster(g, fleet)
+	}
+	return rule.Evaluate(g)
+}
@@ -0,0 +1,168 @@
+package diagnose
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+)
+
+// IMAGE_VERSION_SKEW
+// Reports runtime and Fluid components running different versions, typically after an
+// upgrade that rolled some workloads but not others: pods of one component on more
+// than one image, master, worker and fuse on different image tags, or Fluid controllers
+// on different tags. Runtimes may use different repositories per component, e.g. a
+// separate fuse image, so only tags are compared across components.
+type ImageVersionSkewRule struct{}
+
+func (r *ImageVersionSkewRule) ID() string { return "IMAGE_VERSION_SKEW" }
+
+func (r *ImageVersionSkewRule) Metadata() Metadata {
+	return Metadata{
+		Title:       "Image version skew",
+		Description: "Pods of one component run different images, master/worker/fuse run different image tags, or the Fluid controllers run different tags. `-o wide` adds a per-component image table.",
+		Severity:    "Warning",
+		Component:   "Runtime components, Fluid",
+		Checks:      "The main container image of every runtime pod, the image tags of master, worker and fuse, and the image tags of the Fluid controllers in fluid-system.",
+		Causes:      []string{"An upgrade that rolled some workloads but not others", "Fuse pods that are only replaced once no application uses them"},
+		Remediation: []string{"fluidctl inspect dataset <name> -o wide", "kubectl get deploy,ds -n fluid-system -o wide", "Delete the pods on the old image once they are idle"},
+		Related:     []string{"RUNTIME_WORKLOAD_DRIFT", "POD_IMAGE_PULL_FAILED"},
+	}
+}
+
+func (r *ImageVersionSkewRule) Evaluate(g *types.ResourceGraph) []types.FailureHint {
+	var hints []types.FailureHint
+	if g.Runtime != nil {
+		var tags []string
+		for _, rc := range runtimeComponents(g.Runtime) {
+			if scaledDown(rc.info) {
+				continue
+			}
+			counts := podImageCounts(rc.info.Pods)
+			if len(counts) > 1 {
+				hints = append(hints, types.FailureHint{
+					ID:         r.ID(),
+					Severity:   types.SeverityWarning,
+					Component:  rc.component,
+					Evidence:   factEvidence(workloadKind(rc), rc.info.Name, "Pods run different images: {images}", fact("images", formatImageCounts(counts))),
+					Suggestion: fmt.Sprintf("A rollout of the %s did not complete. Find the pods on the old image and delete them so they are recreated from the current template: kubectl get pods%s -o custom-columns=NAME:.metadata.name,IMAGE:.spec.containers[0].image", workloadKind(rc), namespaceFlag(g)),
+				})
+			}
+			if tag := componentTag(rc.info, counts); tag != "" {
+				tags = append(tags, fmt.Sprintf("%s %s", componentField(rc.component), tag))
+			}
+		}
+		if distinctTags(tags) > 1 {
+			hints = append(hints, types.FailureHint{
+				ID:         r.ID(),
+				Severity:   types.SeverityWarning,
+				Component:  "Runtime",
+				Evidence:   factEvidence(runtimeKind(g), g.Runtime.Name, "Image tags differ: {tags}", fact("tags", strings.Join(tags, ", "))),
+				Suggestion: fmt.Sprintf("Master, worker and fuse should run the same release. Align the image tags in the %s spec, or upgrade Fluid so its controllers roll all components; fuse pods are only replaced once no application uses them.", runtimeKind(g)),
+			})
+		}
+	}
+
+	var controllers []string
+	for _, c := range g.Controllers {
+		if _, tag := types.SplitImage(fluidImage(c.Images)); tag != "" {
+			controllers = append(controllers, fmt.Sprintf("%s %s", c.Name, tag))
+		}
+	}
+	if distinctTags(controllers) > 1 {
+		hints = append(hints, types.FailureHint{
+			ID:         r.ID(),
+			Severity:   types.SeverityWarning,
+			Component:  "Fluid",
+			Evidence:   factEvidence("Namespace", "fluid-system", "Controller image tags differ: {tags}", fact("tags", strings.Join(controllers, ", "))),
+			Suggestion: "The Fluid installation is partly upgraded. Re-run the Helm upgrade of the fluid chart and check that every controller rolled out: kubectl get deploy,ds -n fluid-system -o wide",
+		})
+	}
+	return hints
+}
+
+// imageCount is the number of pods running an image.
+type imageCount struct {
+	image string
+	pods  int
+}
+
+// podImageCounts counts the pods per main container image, most pods first.
+func podImageCounts(pods []types.PodInfo) []imageCount {
+	counts := make(map[string]int)
+	for _, p := range pods {
+		if image := mainImage(p); image != "" {
+			counts[image]++
+		}
+	}
+	out := make([]imageCount, 0, len(counts))
+	for image, n := range counts {
+		out = append(out, imageCount{image, n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].pods != out[j].pods {
+			return out[i].pods > out[j].pods
+		}
+		return out[i].image < out[j].image
+	})
+	return out
+}
+
+// mainImage is the image of a pod's first app container.
+func mainImage(p types.PodInfo) string {
+	for _, c := range p.Containers {
+		if !c.Init {
+			return c.Image
+		}
+	}
+	return ""
+}
+
+func formatImageCounts(counts []imageCount) string {
+	parts := make([]string, len(counts))
+	for i, c := range counts {
+		unit := "pods"
+		if c.pods == 1 {
+			unit = "pod"
+		}
+		parts[i] = fmt.Sprintf("%s (%d %s)", c.image, c.pods, unit)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// componentTag is the image tag most of a component's pods run, or that of its
+// workload if it has no pods.
+func componentTag(c *types.ComponentInfo, counts []imageCount) string {
+	if len(counts) > 0 {
+		_, tag := types.SplitImage(counts[0].image)
+		return tag
+	}
+	if c.Actual != nil {
+		return c.Actual.ImageTag
+	}
+	return ""
+}
+
+// fluidImage picks a controller's own image among its containers, skipping
+// sidecars such as the CSI node-driver-registrar.
+func fluidImage(images []string) string {
+	for _, image := range images {
+		if repo, _ := types.SplitImage(image); strings.Contains(repo, "fluid") {
+			return image
+		}
+	}
+	if len(images) > 0 {
+		return images[0]
+	}
+	return ""
+}
+
+// distinctTags counts the distinct tags of "<name> <tag>" entries.
+func distinctTags(entries []string) int {
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		seen[e[strings.LastIndex(e, " ")+1:]] = true
+	}
+	return len(seen)
+}
@@ -3,15 +3,21 @@ package mapper
 import (
 	"context"
 	"fmt"
+	"path"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/k8s"
 	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/duration"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -28,7 +34,109 @@ func NewK8sMapper(c k8s.Client) *K8sMapper {
 
 // MapDataset discovers the Dataset and all related resources in the cluster.
 func (m *K8sMapper) MapDataset(ctx context.Context, name, namespace string) (*types.ResourceGraph, error) {
-	graph := &types.ResourceGraph{}
+	cluster, err := m.mapClusterScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := m.mapNamespaceScope(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return m.mapDataset(ctx, name, namespace, cluster, scope)
+}
+
+// MapAll maps every Dataset of a namespace, or of the cluster if namespace is empty,
+// sorted by namespace and name. The node inventory is listed once and shared, and the
+// pods and events of each namespace once for all its Datasets.
+func (m *K8sMapper) MapAll(ctx context.Context, namespace string) ([]*types.ResourceGraph, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "data.fluid.io",
+		Version: "v1alpha1",
+		Kind:    "DatasetList",
+	})
+	var opts []client.ListOption
+	if namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
+	}
+	if err := m.client.List(ctx, list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list datasets: %w", err)
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		if list.Items[i].GetNamespace() != list.Items[j].GetNamespace() {
+			return list.Items[i].GetNamespace() < list.Items[j].GetNamespace()
+		}
+		return list.Items[i].GetName() < list.Items[j].GetName()
+	})
+
+	cluster, err := m.mapClusterScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var graphs []*types.ResourceGraph
+	var scope *namespaceScope
+	for i, u := range list.Items {
+		if i == 0 || u.GetNamespace() != list.Items[i-1].GetNamespace() {
+			if scope, err = m.mapNamespaceScope(ctx, u.GetNamespace()); err != nil {
+				return nil, err
+			}
+		}
+		graph, err := m.mapDataset(ctx, u.GetName(), u.GetNamespace(), cluster, scope)
+		if err != nil {
+			return nil, fmt.Errorf("dataset %s/%s: %w", u.GetNamespace(), u.GetName(), err)
+		}
+		graphs = append(graphs, graph)
+	}
+	return graphs, nil
+}
+
+// clusterScope holds what all Datasets of a cluster share.
+type clusterScope struct {
+	nodes       []types.NodeInfo
+	controllers []types.ControllerInfo
+}
+
+func (m *K8sMapper) mapClusterScope(ctx context.Context) (*clusterScope, error) {
+	nodes, err := m.mapNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	controllers, err := m.mapControllers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Fluid controllers: %w", err)
+	}
+	return &clusterScope{nodes: nodes, controllers: controllers}, nil
+}
+
+// namespaceScope holds what all Datasets of a namespace share. Pods are nil when they
+// cannot be listed; events are then simply empty.
+type namespaceScope struct {
+	pods   []corev1.Pod
+	events []corev1.Event
+}
+
+func (m *K8sMapper) mapNamespaceScope(ctx context.Context, namespace string) (*namespaceScope, error) {
+	scope := &namespaceScope{}
+	podList := &corev1.PodList{}
+	switch err := m.client.List(ctx, podList, client.InNamespace(namespace)); {
+	case err == nil:
+		scope.pods = append([]corev1.Pod{}, podList.Items...) // non-nil: listed, maybe empty
+	case !apierrors.IsForbidden(err):
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	eventList := &corev1.EventList{}
+	switch err := m.client.List(ctx, eventList, client.InNamespace(namespace)); {
+	case err == nil:
+		scope.events = eventList.Items
+	case !apierrors.IsForbidden(err):
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	return scope, nil
+}
+
+func (m *K8sMapper) mapDataset(ctx context.Context, name, namespace string, cluster *clusterScope, scope *namespaceScope) (*types.ResourceGraph, error) {
+	graph := &types.ResourceGraph{ObservedAt: time.Now(), Nodes: cluster.nodes, Controllers: cluster.controllers}
 
 	// 1. Discover Dataset
 	datasetInfo, err := m.mapDatasetCR(ctx, name, namespace)
@@ -59,9 +167,240 @@ func (m *K8sMapper) MapDataset(ctx context.Context, name, namespace string) (*ty
 	}
 	graph.Infrastructure = infraInfo
 
+	// 4. Discover the Secrets the mounts read credentials from: key names only.
+	if graph.Secrets, err = m.mapSecrets(ctx, datasetInfo.Mounts, namespace); err != nil {
+		return nil, fmt.Errorf("failed to get secrets: %w", err)
+	}
+
+	// 5. Discover the application pods mounting the Dataset; Fluid names the PVC after it.
+	graph.Consumers = mapConsumers(name, scope.pods)
+
+	// 6. Discover scheduling context: events (the node inventory is passed in).
+	graph.Events = mapEvents(graph, scope.events)
+
 	return graph, nil
 }
 
+// mapNodes lists the cluster's nodes, sorted by name. Nodes are cluster-scoped, so users
+// with namespace-scoped permissions only may not read them; the inventory is then empty.
+func (m *K8sMapper) mapNodes(ctx context.Context) ([]types.NodeInfo, error) {
+	nodeList := &corev1.NodeList{}
+	if err := m.client.List(ctx, nodeList); err != nil {
+		if apierrors.IsForbidden(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	sort.Slice(nodeList.Items, func(i, j int) bool {
+		return nodeList.Items[i].Name < nodeList.Items[j].Name
+	})
+
+	var nodes []types.NodeInfo
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		info := types.NodeInfo{
+			Name:          node.Name,
+			Unschedulable: node.Spec.Unschedulable,
+			Labels:        node.Labels,
+			Taints:        node.Spec.Taints,
+			Allocatable:   node.Status.Allocatable,
+			Object:        node,
+		}
+		for _, cond := range node.Status.Conditions {
+			switch cond.Type {
+			case corev1.NodeReady:
+				info.Ready = cond.Status == corev1.ConditionTrue
+			case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure:
+				if cond.Status == corev1.ConditionTrue {
+					info.Pressures = append(info.Pressures, string(cond.Type))
+				}
+			}
+		}
+		nodes = append(nodes, info)
+	}
+	return nodes, nil
+}
+
+// FluidNamespace is where Fluid installs its controllers.
+const FluidNamespace = "fluid-system"
+
+// mapControllers lists the Deployments and DaemonSets of the Fluid installation, sorted
+// by name. Like nodes, they may not be readable with namespace-scoped permissions.
+func (m *K8sMapper) mapControllers(ctx context.Context) ([]types.ControllerInfo, error) {
+	deployments := &appsv1.DeploymentList{}
+	if err := m.client.List(ctx, deployments, client.InNamespace(FluidNamespace)); err != nil {
+		if apierrors.IsForbidden(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	daemonSets := &appsv1.DaemonSetList{}
+	if err := m.client.List(ctx, daemonSets, client.InNamespace(FluidNamespace)); err != nil {
+		if apierrors.IsForbidden(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var controllers []types.ControllerInfo
+	for _, d := range deployments.Items {
+		replicas := int32(1)
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		controllers = append(controllers, types.ControllerInfo{
+			Name:     d.Name,
+			Kind:     "Deployment",
+			Images:   containerImages(d.Spec.Template.Spec.Containers),
+			Replicas: replicas,
+			Ready:    d.Status.ReadyReplicas,
+		})
+	}
+	for _, ds := range daemonSets.Items {
+		controllers = append(controllers, types.ControllerInfo{
+			Name:     ds.Name,
+			Kind:     "DaemonSet",
+			Images:   containerImages(ds.Spec.Template.Spec.Containers),
+			Replicas: ds.Status.DesiredNumberScheduled,
+			Ready:    ds.Status.NumberReady,
+		})
+	}
+	sort.Slice(controllers, func(i, j int) bool {
+		return controllers[i].Name < controllers[j].Name
+	})
+	return controllers, nil
+}
+
+func containerImages(containers []corev1.Container) []string {
+	images := make([]string, 0, len(containers))
+	for _, c := range containers {
+		images = append(images, c.Image)
+	}
+	return images
+}
+
+// mapConsumers picks the pods that mount the PVC claimName, sorted by name. The result
+// is empty, not nil, when none does, and nil when pods could not be listed.
+func mapConsumers(claimName string, pods []corev1.Pod) []types.PodInfo {
+	if pods == nil {
+		return nil
+	}
+	consumers := []types.PodInfo{}
+	for i := range pods {
+		pod := &pods[i]
+		for _, v := range pod.Spec.Volumes {
+			if v.PersistentVolumeClaim != nil && v.PersistentVolumeClaim.ClaimName == claimName {
+				consumers = append(consumers, mapPod(pod))
+				break
+			}
+		}
+	}
+	sort.Slice(consumers, func(i, j int) bool {
+		return consumers[i].Name < consumers[j].Name
+	})
+	return consumers
+}
+
+// mapSecrets looks up the Secrets referenced by the mounts' encrypt options, sorted by
+// name. Only key names are kept; values never leave this function. Without permission
+// to read Secrets nothing is collected.
+func (m *K8sMapper) mapSecrets(ctx context.Context, mounts []types.MountInfo, namespace string) ([]types.SecretInfo, error) {
+	names := make(map[string]bool)
+	for _, mount := range mounts {
+		for _, o := range mount.EncryptOptions {
+			if o.SecretName != "" {
+				names[o.SecretName] = true
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	secrets := make([]types.SecretInfo, 0, len(names))
+	for name := range names {
+		info := types.SecretInfo{Name: name}
+		secret := &corev1.Secret{}
+		err := m.client.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, secret)
+		switch {
+		case err == nil:
+			info.Found = true
+			for k := range secret.Data {
+				info.Keys = append(info.Keys, k)
+			}
+			sort.Strings(info.Keys)
+		case apierrors.IsNotFound(err):
+		case apierrors.IsForbidden(err):
+			return nil, nil
+		default:
+			return nil, err
+		}
+		secrets = append(secrets, info)
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].Name < secrets[j].Name
+	})
+	return secrets, nil
+}
+
+// mapEvents picks the namespace's events about objects of the graph, oldest first.
+// Events only add detail, so without permission to list them there are none.
+func mapEvents(g *types.ResourceGraph, namespaceEvents []corev1.Event) []types.EventInfo {
+	involved := graphObjects(g)
+	var events []types.EventInfo
+	for _, e := range namespaceEvents {
+		if !involved[e.InvolvedObject.Kind+"/"+e.InvolvedObject.Name] {
+			continue
+		}
+		last := e.LastTimestamp.Time
+		if last.IsZero() {
+			last = e.EventTime.Time
+		}
+		events = append(events, types.EventInfo{
+			Kind:          e.InvolvedObject.Kind,
+			Name:          e.InvolvedObject.Name,
+			Type:          e.Type,
+			Reason:        e.Reason,
+			Message:       e.Message,
+			Count:         e.Count,
+			LastTimestamp: last,
+		})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].LastTimestamp.Equal(events[j].LastTimestamp) {
+			return events[i].LastTimestamp.Before(events[j].LastTimestamp)
+		}
+		return events[i].Name < events[j].Name
+	})
+	return events
+}
+
+// graphObjects returns the "Kind/Name" keys of the namespaced objects of the graph.
+func graphObjects(g *types.ResourceGraph) map[string]bool {
+	objects := map[string]bool{"Dataset/" + g.Dataset.Name: true}
+	if g.Runtime != nil {
+		objects[g.Runtime.Type+"/"+g.Runtime.Name] = true
+		for _, c := range []*types.ComponentInfo{g.Runtime.Master, g.Runtime.Worker, g.Runtime.Fuse} {
+			if c == nil {
+				continue
+			}
+			if c.StatefulSet != nil {
+				objects["StatefulSet/"+c.Name] = true
+			}
+			if c.DaemonSet != nil {
+				objects["DaemonSet/"+c.Name] = true
+			}
+			for _, p := range c.Pods {
+				objects["Pod/"+p.Name] = true
+			}
+		}
+	}
+	if g.Infrastructure != nil && g.Infrastructure.PVC != nil {
+		objects["PersistentVolumeClaim/"+g.Infrastructure.PVC.Name] = true
+	}
+	return objects
+}
+
 // mapDatasetCR fetches the Dataset CR via Unstructured.
 func (m *K8sMapper) mapDatasetCR(ctx context.Context, name, namespace string) (*types.DatasetInfo, error) {
 	u := &unstructured.Unstructured{}
@@ -91,16 +430,91 @@ func (m *K8sMapper) mapDatasetCR(ctx context.Context, name, namespace string) (*
 		status = "Bound"
 	}
 
+	// Acknowledged issues. A malformed annotation is reported, not fatal.
+	silences, err := types.ParseSilences(u.GetAnnotations()[types.SilenceAnnotation])
+	silenceError := ""
+	if err != nil {
+		silenceError = err.Error()
+	}
+
 	return &types.DatasetInfo{
-		Name:      u.GetName(),
-		Namespace: u.GetNamespace(),
-		Status:    status,
-		Phase:     statusPhase,
-		Labels:    u.GetLabels(),
-		Object:    u, // Store raw object for debugging/extensions
+		Name:               u.GetName(),
+		Namespace:          u.GetNamespace(),
+		Status:             status,
+		Phase:              statusPhase,
+		Labels:             u.GetLabels(),
+		CreationTimestamp:  u.GetCreationTimestamp().Time,
+		LastTransitionTime: latestConditionTransition(u),
+		Silences:           silences,
+		SilenceError:       silenceError,
+		Mounts:             datasetMounts(u),
+		Object:             u, // Store raw object for debugging/extensions
 	}, nil
 }
 
+// datasetMounts reads spec.mounts, merging spec.sharedOptions and
+// spec.sharedEncryptOptions into every mount as Fluid does. Option values are dropped.
+func datasetMounts(u *unstructured.Unstructured) []types.MountInfo {
+	raw, _, _ := unstructured.NestedSlice(u.Object, "spec", "mounts")
+	sharedOptions, _, _ := unstructured.NestedStringMap(u.Object, "spec", "sharedOptions")
+	sharedEncrypt, _, _ := unstructured.NestedSlice(u.Object, "spec", "sharedEncryptOptions")
+
+	var mounts []types.MountInfo
+	for _, item := range raw {
+		mount, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		info := types.MountInfo{}
+		info.Name, _, _ = unstructured.NestedString(mount, "name")
+		info.MountPoint, _, _ = unstructured.NestedString(mount, "mountPoint")
+
+		options, _, _ := unstructured.NestedStringMap(mount, "options")
+		keys := make(map[string]bool)
+		for k := range sharedOptions {
+			keys[k] = true
+		}
+		for k := range options {
+			keys[k] = true
+		}
+		for k := range keys {
+			info.OptionKeys = append(info.OptionKeys, k)
+		}
+		sort.Strings(info.OptionKeys)
+
+		// Options of the mount override shared ones of the same name.
+		encrypt, _, _ := unstructured.NestedSlice(mount, "encryptOptions")
+		byName := make(map[string]types.EncryptOption)
+		for _, o := range append(encryptOptions(sharedEncrypt), encryptOptions(encrypt)...) {
+			byName[o.Name] = o
+		}
+		for _, o := range byName {
+			info.EncryptOptions = append(info.EncryptOptions, o)
+		}
+		sort.Slice(info.EncryptOptions, func(i, j int) bool {
+			return info.EncryptOptions[i].Name < info.EncryptOptions[j].Name
+		})
+		mounts = append(mounts, info)
+	}
+	return mounts
+}
+
+// encryptOptions reads a list of {name, valueFrom.secretKeyRef{name, key}}.
+func encryptOptions(raw []interface{}) []types.EncryptOption {
+	var out []types.EncryptOption
+	for _, item := range raw {
+		o, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(o, "name")
+		secretName, _, _ := unstructured.NestedString(o, "valueFrom", "secretKeyRef", "name")
+		secretKey, _, _ := unstructured.NestedString(o, "valueFrom", "secretKeyRef", "key")
+		out = append(out, types.EncryptOption{Name: name, SecretName: secretName, SecretKey: secretKey})
+	}
+	return out
+}
+
 // discoverRuntime attempts to find the matching Runtime CR.
 func (m *K8sMapper) discoverRuntime(ctx context.Context, name, namespace string) (*types.RuntimeInfo, error) {
 	// Priority list of runtimes to check
@@ -130,10 +544,27 @@ func (m *K8sMapper) discoverRuntime(ctx context.Context, name, namespace string)
 
 func (m *K8sMapper) mapRuntime(ctx context.Context, u *unstructured.Unstructured, kind string) (*types.RuntimeInfo, error) {
 	info := &types.RuntimeInfo{
-		Name:   u.GetName(),
-		Type:   kind,
-		Phase:  getNestedString(u, "status", "phase"),
-		Object: u,
+		Name:               u.GetName(),
+		Type:               kind,
+		Phase:              getNestedString(u, "status", "phase"),
+		CreationTimestamp:  u.GetCreationTimestamp().Time,
+		LastTransitionTime: latestConditionTransition(u),
+		TieredStore:        tieredStoreLevels(u),
+		Object:             u,
+	}
+
+	// ThinRuntimes take their file system from a cluster-scoped ThinRuntimeProfile.
+	if kind == "ThinRuntime" {
+		info.ProfileName = getNestedString(u, "spec", "profileName")
+		profile, found, err := m.thinRuntimeProfile(ctx, info.ProfileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ThinRuntimeProfile %s: %w", info.ProfileName, err)
+		}
+		info.ProfileFound = found
+		info.FuseImage = getNestedString(u, "spec", "fuse", "image")
+		if info.FuseImage == "" && profile != nil {
+			info.FuseImage = getNestedString(profile, "spec", "fuse", "image")
+		}
 	}
 
 	// Inspect Workloads (StatefulSets/DaemonSets)
@@ -145,11 +576,13 @@ func (m *K8sMapper) mapRuntime(ctx context.Context, u *unstructured.Unstructured
 	masterSTS, err := m.getReadyStatefulSet(ctx, masterName, u.GetNamespace())
 	if err == nil && masterSTS != nil {
 		info.Master = &types.ComponentInfo{
-			Name:        masterName,
-			Replicas:    *masterSTS.Spec.Replicas,
-			Ready:       masterSTS.Status.ReadyReplicas,
-			State:       determineComponentState(masterSTS.Status.ReadyReplicas, *masterSTS.Spec.Replicas),
-			StatefulSet: masterSTS,
+			Name:              masterName,
+			Replicas:          statefulSetReplicas(masterSTS),
+			Ready:             masterSTS.Status.ReadyReplicas,
+			State:             determineComponentState(masterSTS.Status.ReadyReplicas, statefulSetReplicas(masterSTS)),
+			CreationTimestamp: masterSTS.CreationTimestamp.Time,
+			StatefulSet:       masterSTS,
+			NodeSelector:      masterSTS.Spec.Template.Spec.NodeSelector,
 		}
 	}
 
@@ -159,22 +592,26 @@ func (m *K8sMapper) mapRuntime(ctx context.Context, u *unstructured.Unstructured
 	workerSTS, err := m.getReadyStatefulSet(ctx, workerName, u.GetNamespace())
 	if err == nil && workerSTS != nil {
 		info.Worker = &types.ComponentInfo{
-			Name:        workerName,
-			Replicas:    *workerSTS.Spec.Replicas,
-			Ready:       workerSTS.Status.ReadyReplicas,
-			State:       determineComponentState(workerSTS.Status.ReadyReplicas, *workerSTS.Spec.Replicas),
-			StatefulSet: workerSTS,
+			Name:              workerName,
+			Replicas:          statefulSetReplicas(workerSTS),
+			Ready:             workerSTS.Status.ReadyReplicas,
+			State:             determineComponentState(workerSTS.Status.ReadyReplicas, statefulSetReplicas(workerSTS)),
+			CreationTimestamp: workerSTS.CreationTimestamp.Time,
+			StatefulSet:       workerSTS,
+			NodeSelector:      workerSTS.Spec.Template.Spec.NodeSelector,
 		}
 	} else {
 		// Try DaemonSet
 		workerDS, err := m.getReadyDaemonSet(ctx, workerName, u.GetNamespace())
 		if err == nil && workerDS != nil {
 			info.Worker = &types.ComponentInfo{
-				Name:      workerName,
-				Replicas:  workerDS.Status.DesiredNumberScheduled,
-				Ready:     workerDS.Status.NumberReady,
-				State:     determineComponentState(workerDS.Status.NumberReady, workerDS.Status.DesiredNumberScheduled),
-				DaemonSet: workerDS,
+				Name:              workerName,
+				Replicas:          workerDS.Status.DesiredNumberScheduled,
+				Ready:             workerDS.Status.NumberReady,
+				State:             determineComponentState(workerDS.Status.NumberReady, workerDS.Status.DesiredNumberScheduled),
+				CreationTimestamp: workerDS.CreationTimestamp.Time,
+				DaemonSet:         workerDS,
+				NodeSelector:      workerDS.Spec.Template.Spec.NodeSelector,
 			}
 		}
 	}
@@ -184,17 +621,273 @@ func (m *K8sMapper) mapRuntime(ctx context.Context, u *unstructured.Unstructured
 	fuseDS, err := m.getReadyDaemonSet(ctx, fuseName, u.GetNamespace())
 	if err == nil && fuseDS != nil {
 		info.Fuse = &types.ComponentInfo{
-			Name:      fuseName,
-			Replicas:  fuseDS.Status.DesiredNumberScheduled,
-			Ready:     fuseDS.Status.NumberReady,
-			State:     determineComponentState(fuseDS.Status.NumberReady, fuseDS.Status.DesiredNumberScheduled),
-			DaemonSet: fuseDS,
+			Name:              fuseName,
+			Replicas:          fuseDS.Status.DesiredNumberScheduled,
+			Ready:             fuseDS.Status.NumberReady,
+			State:             determineComponentState(fuseDS.Status.NumberReady, fuseDS.Status.DesiredNumberScheduled),
+			CreationTimestamp: fuseDS.CreationTimestamp.Time,
+			DaemonSet:         fuseDS,
+			NodeSelector:      fuseDS.Spec.Template.Spec.NodeSelector,
+		}
+	}
+
+	// Desired state from the Runtime spec next to the workload's actual pod template.
+	for _, rc := range []struct {
+		role string
+		c    *types.ComponentInfo
+	}{{"master", info.Master}, {"worker", info.Worker}, {"fuse", info.Fuse}} {
+		if rc.c != nil {
+			rc.c.Desired = desiredSpec(u, kind, rc.role)
+			rc.c.Actual = actualSpec(rc.c)
+		}
+	}
+
+	// Pods of each component, selected by the workload's label selector.
+	for _, c := range []*types.ComponentInfo{info.Master, info.Worker, info.Fuse} {
+		if c == nil {
+			continue
+		}
+		if err := m.mapPods(ctx, c, u.GetNamespace()); err != nil {
+			return nil, fmt.Errorf("failed to list pods of %s: %w", c.Name, err)
 		}
 	}
 
 	return info, nil
 }
 
+// thinRuntimeProfile gets the named ThinRuntimeProfile and reports whether it exists.
+// It returns a nil profile if it does not, and a nil found when there is no name or
+// profiles cannot be read.
+func (m *K8sMapper) thinRuntimeProfile(ctx context.Context, name string) (*unstructured.Unstructured, *bool, error) {
+	if name == "" {
+		return nil, nil, nil
+	}
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(schema.GroupVersionKind{Group: "data.fluid.io", Version: "v1alpha1", Kind: "ThinRuntimeProfile"})
+	found := true
+	if err := m.client.Get(ctx, client.ObjectKey{Name: name}, u); err != nil {
+		switch {
+		case apierrors.IsNotFound(err):
+			found = false
+			u = nil
+		case apierrors.IsForbidden(err):
+			return nil, nil, nil
+		default:
+			return nil, nil, err
+		}
+	}
+	return u, &found, nil
+}
+
+// mapPods lists the pods owned by a component's workload and attaches them, sorted by name.
+// The component's LastTransitionTime is set to the latest readiness transition among them.
+// Without permission to list pods, Pods stays nil and rules fall back to the workload.
+func (m *K8sMapper) mapPods(ctx context.Context, c *types.ComponentInfo, namespace string) error {
+	var selector *metav1.LabelSelector
+	if c.StatefulSet != nil {
+		selector = c.StatefulSet.Spec.Selector
+	} else if c.DaemonSet != nil {
+		selector = c.DaemonSet.Spec.Selector
+	}
+	if selector == nil {
+		return nil
+	}
+
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return err
+	}
+
+	podList := &corev1.PodList{}
+	if err := m.client.List(ctx, podList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: sel}); err != nil {
+		if apierrors.IsForbidden(err) {
+			c.Images = componentImages(c)
+			return nil
+		}
+		return err
+	}
+	sort.Slice(podList.Items, func(i, j int) bool {
+		return podList.Items[i].Name < podList.Items[j].Name
+	})
+
+	for i := range podList.Items {
+		pod := mapPod(&podList.Items[i])
+		if pod.LastTransitionTime.After(c.LastTransitionTime) {
+			c.LastTransitionTime = pod.LastTransitionTime
+		}
+		c.Pods = append(c.Pods, pod)
+	}
+	c.Images = componentImages(c)
+	return nil
+}
+
+// componentImages lists the distinct app container images of a component's pods,
+// sorted, or those of its pod template if it has no pods.
+func componentImages(c *types.ComponentInfo) []string {
+	seen := make(map[string]bool)
+	var images []string
+	for _, p := range c.Pods {
+		for _, ctr := range p.Containers {
+			if !ctr.Init && ctr.Image != "" && !seen[ctr.Image] {
+				seen[ctr.Image] = true
+				images = append(images, ctr.Image)
+			}
+		}
+	}
+	if len(images) == 0 {
+		if c.StatefulSet != nil {
+			images = containerImages(c.StatefulSet.Spec.Template.Spec.Containers)
+		} else if c.DaemonSet != nil {
+			images = containerImages(c.DaemonSet.Spec.Template.Spec.Containers)
+		}
+	}
+	sort.Strings(images)
+	return images
+}
+
+// mapPod converts a Pod into a PodInfo.
+func mapPod(pod *corev1.Pod) types.PodInfo {
+	info := types.PodInfo{
+		Name:              pod.Name,
+		Status:            podStatus(pod),
+		Phase:             string(pod.Status.Phase),
+		Reason:            pod.Status.Reason,
+		Message:           pod.Status.Message,
+		Node:              pod.Spec.NodeName,
+		NodeSelector:      pod.Spec.NodeSelector,
+		Requests:          podRequests(pod),
+		HostPorts:         podHostPorts(pod),
+		HostPaths:         podHostPaths(pod),
+		Age:               duration.HumanDuration(time.Since(pod.CreationTimestamp.Time)),
+		CreationTimestamp: pod.CreationTimestamp.Time,
+		Object:            pod,
+	}
+
+	for _, cond := range pod.Status.Conditions {
+		switch cond.Type {
+		case corev1.PodReady:
+			info.Ready = cond.Status == corev1.ConditionTrue
+			info.LastTransitionTime = cond.LastTransitionTime.Time
+		case corev1.PodScheduled:
+			if cond.Status == corev1.ConditionFalse {
+				info.Unschedulable = cond.Message
+				if info.Unschedulable == "" {
+					info.Unschedulable = cond.Reason
+				}
+			}
+		}
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		info.Restarts += cs.RestartCount
+		if info.LastState == nil && cs.LastTerminationState.Terminated != nil {
+			last := cs.LastTerminationState
+			info.LastState = &last
+		}
+	}
+
+	info.Containers = append(mapContainers(pod.Spec.InitContainers, pod.Status.InitContainerStatuses, true),
+		mapContainers(pod.Spec.Containers, pod.Status.ContainerStatuses, false)...)
+	return info
+}
+
+// podRequests sums the resource requests of the pod's app containers.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	var total corev1.ResourceList
+	for _, c := range pod.Spec.Containers {
+		for name, q := range c.Resources.Requests {
+			if total == nil {
+				total = corev1.ResourceList{}
+			}
+			sum := total[name]
+			sum.Add(q)
+			total[name] = sum
+		}
+	}
+	return total
+}
+
+// podHostPorts lists the node ports a pod binds, sorted. Under hostNetwork every
+// container port is a host port.
+func podHostPorts(pod *corev1.Pod) []int32 {
+	seen := make(map[int32]bool)
+	var ports []int32
+	for _, c := range append(append([]corev1.Container(nil), pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		for _, p := range c.Ports {
+			port := p.HostPort
+			if pod.Spec.HostNetwork && port == 0 {
+				port = p.ContainerPort
+			}
+			if port != 0 && !seen[port] {
+				seen[port] = true
+				ports = append(ports, port)
+			}
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	return ports
+}
+
+// podHostPaths lists the paths of a pod's hostPath volumes, sorted.
+func podHostPaths(pod *corev1.Pod) []string {
+	var paths []string
+	for _, v := range pod.Spec.Volumes {
+		if v.HostPath != nil {
+			paths = append(paths, path.Clean(v.HostPath.Path))
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// mapContainers joins container specs with their statuses by name.
+func mapContainers(specs []corev1.Container, statuses []corev1.ContainerStatus, init bool) []types.ContainerInfo {
+	byName := make(map[string]corev1.ContainerStatus, len(statuses))
+	for _, cs := range statuses {
+		byName[cs.Name] = cs
+	}
+
+	var containers []types.ContainerInfo
+	for _, c := range specs {
+		info := types.ContainerInfo{
+			Name:  c.Name,
+			Image: c.Image,
+			Init:  init,
+		}
+		if limit, ok := c.Resources.Limits[corev1.ResourceMemory]; ok {
+			info.MemoryLimit = limit.String()
+		}
+		if cs, ok := byName[c.Name]; ok {
+			info.Ready = cs.Ready
+			info.RestartCount = cs.RestartCount
+			state, last := cs.State, cs.LastTerminationState
+			info.State = &state
+			if last.Terminated != nil {
+				info.LastState = &last
+			}
+		}
+		containers = append(containers, info)
+	}
+	return containers
+}
+
+// podStatus mirrors the STATUS column of `kubectl get pods`: the pod reason (e.g. Evicted),
+// then the waiting or terminated reason of the first unhealthy container, then the phase.
+func podStatus(pod *corev1.Pod) string {
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			return cs.State.Waiting.Reason
+		}
+		if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
+			return cs.State.Terminated.Reason
+		}
+	}
+	return string(pod.Status.Phase)
+}
+
 func (m *K8sMapper) getReadyStatefulSet(ctx context.Context, name, namespace string) (*appsv1.StatefulSet, error) {
 	sts := &appsv1.StatefulSet{}
 	key := client.ObjectKey{Name: name, Namespace: namespace}
@@ -222,9 +915,10 @@ func (m *K8sMapper) discoverInfrastructure(ctx context.Context, name, namespace
 	key := client.ObjectKey{Name: name, Namespace: namespace}
 	if err := m.client.Get(ctx, key, pvc); err == nil {
 		infra.PVC = &types.PVCInfo{
-			Name:   pvc.Name,
-			Status: string(pvc.Status.Phase),
-			Object: pvc,
+			Name:              pvc.Name,
+			Status:            string(pvc.Status.Phase),
+			CreationTimestamp: pvc.CreationTimestamp.Time,
+			Object:            pvc,
 		}
 
 		// If bound, fetch PV
@@ -253,6 +947,35 @@ func getNestedString(u *unstructured.Unstructured, fields ...string) string {
 	return val
 }
 
+// latestConditionTransition returns the most recent lastTransitionTime found in
+// status.conditions of a Fluid CR, or the zero time if there is none.
+func latestConditionTransition(u *unstructured.Unstructured) time.Time {
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+
+	var latest time.Time
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		raw, _ := cond["lastTransitionTime"].(string)
+		t, err := time.Parse(time.RFC3339, raw)
+		if err == nil && t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+// statefulSetReplicas returns the desired replicas of a StatefulSet; the API server
+// defaults an unset count to 1, but objects from other sources may leave it nil.
+func statefulSetReplicas(sts *appsv1.StatefulSet) int32 {
+	if sts.Spec.Replicas == nil {
+		return 1
+	}
+	return *sts.Spec.Replicas
+}
+
 func determineComponentState(ready, desired int32) string {
 	if desired == 0 {
 		return "ComponentsScaledDown"
@@ -265,3 +988,124 @@ func determineComponentState(ready, desired int32) string {
 	}
 	return "NotReady"
 }
+
+// tieredStoreLevels parses spec.tieredstore.levels of a Runtime. A level caches up
+// to quota, or with several comma-separated paths, up to the sum of quotaList.
+// Unparsable quotas are left zero.
+func tieredStoreLevels(u *unstructured.Unstructured) []types.TieredStoreLevel {
+	raw, _, _ := unstructured.NestedSlice(u.Object, "spec", "tieredstore", "levels")
+	var levels []types.TieredStoreLevel
+	for _, item := range raw {
+		l, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		mediumType, _, _ := unstructured.NestedString(l, "mediumtype")
+		paths, _, _ := unstructured.NestedString(l, "path")
+		level := types.TieredStoreLevel{MediumType: mediumType, Paths: splitList(paths)}
+
+		if quotaList, _, _ := unstructured.NestedString(l, "quotaList"); quotaList != "" {
+			for _, q := range splitList(quotaList) {
+				if parsed, err := resource.ParseQuantity(q); err == nil {
+					level.Quota.Add(parsed)
+				}
+			}
+		} else if q, ok := l["quota"]; ok {
+			if parsed, err := resource.ParseQuantity(fmt.Sprint(q)); err == nil {
+				level.Quota = parsed
+			}
+		}
+		levels = append(levels, level)
+	}
+	return levels
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// runtimeVersionKeys names the spec field holding the master and worker image of each Runtime kind.
+var runtimeVersionKeys = map[string]string{
+	"AlluxioRuntime": "alluxioVersion",
+	"JindoRuntime":   "jindoVersion",
+	"JuiceFSRuntime": "juicefsVersion",
+}
+
+// desiredSpec reads a component's replicas, image and resource requests from the Runtime spec.
+// Workers are counted by spec.replicas; the fuse image is set separately in spec.fuse.
+func desiredSpec(u *unstructured.Unstructured, kind, role string) *types.ComponentSpec {
+	spec := &types.ComponentSpec{}
+	switch role {
+	case "master":
+		spec.Replicas = nestedInt32(u, "spec", "master", "replicas")
+	case "worker":
+		if spec.Replicas = nestedInt32(u, "spec", "replicas"); spec.Replicas == nil {
+			spec.Replicas = nestedInt32(u, "spec", "worker", "replicas")
+		}
+	}
+
+	if role == "fuse" {
+		spec.Image = getNestedString(u, "spec", "fuse", "image")
+		spec.ImageTag = getNestedString(u, "spec", "fuse", "imageTag")
+	} else if key := runtimeVersionKeys[kind]; key != "" {
+		spec.Image = getNestedString(u, "spec", key, "image")
+		spec.ImageTag = getNestedString(u, "spec", key, "imageTag")
+	}
+
+	requests, _, _ := unstructured.NestedMap(u.Object, "spec", role, "resources", "requests")
+	for name, v := range requests {
+		q, err := resource.ParseQuantity(fmt.Sprint(v))
+		if err != nil {
+			continue
+		}
+		if spec.Requests == nil {
+			spec.Requests = corev1.ResourceList{}
+		}
+		spec.Requests[corev1.ResourceName(name)] = q
+	}
+	return spec
+}
+
+// actualSpec reads the replicas, and the image and requests of the main (first)
+// container, from a component's workload.
+func actualSpec(c *types.ComponentInfo) *types.ComponentSpec {
+	var template *corev1.PodTemplateSpec
+	spec := &types.ComponentSpec{}
+	if c.StatefulSet != nil {
+		spec.Replicas = c.StatefulSet.Spec.Replicas
+		template = &c.StatefulSet.Spec.Template
+	} else if c.DaemonSet != nil {
+		template = &c.DaemonSet.Spec.Template
+	}
+	if template == nil || len(template.Spec.Containers) == 0 {
+		return spec
+	}
+	main := template.Spec.Containers[0]
+	spec.Image, spec.ImageTag = types.SplitImage(main.Image)
+	spec.Requests = main.Resources.Requests
+	return spec
+}
+
+// nestedInt32 reads an integer field, which decodes as int64 or float64 depending on the source.
+func nestedInt32(u *unstructured.Unstructured, fields ...string) *int32 {
+	v, found, err := unstructured.NestedFieldNoCopy(u.Object, fields...)
+	if !found || err != nil {
+		return nil
+	}
+	var n int32
+	switch x := v.(type) {
+	case int64:
+		n = int32(x)
+	case float64:
+		n = int32(x)
+	default:
+		return nil
+	}
+	return &n
+}
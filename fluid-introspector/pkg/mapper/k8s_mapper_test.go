@@ -0,0 +1,328 @@
+package mapper
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// fakeMapper returns a mapper over a fake API server holding objs. Listing any of
+// the forbidden list types fails as it does without RBAC permission.
+func fakeMapper(t *testing.T, objs []client.Object, forbidden ...client.ObjectList) *K8sMapper {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				for _, f := range forbidden {
+					if sameType(list, f) {
+						return apierrors.NewForbidden(schema.GroupResource{Resource: "list"}, "", nil)
+					}
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).Build()
+	return &K8sMapper{client: c}
+}
+
+func sameType(a, b client.ObjectList) bool {
+	switch a.(type) {
+	case *corev1.PodList:
+		_, ok := b.(*corev1.PodList)
+		return ok
+	case *corev1.EventList:
+		_, ok := b.(*corev1.EventList)
+		return ok
+	case *appsv1.DeploymentList:
+		_, ok := b.(*appsv1.DeploymentList)
+		return ok
+	}
+	return false
+}
+
+func workerStatefulSet() *appsv1.StatefulSet {
+	replicas := int32(2)
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-worker", Namespace: "default"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "alluxio-worker"}},
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "worker", Image: "alluxio/alluxio:2.9.0"}}}},
+		},
+	}
+}
+
+func TestMapPods_Forbidden(t *testing.T) {
+	m := fakeMapper(t, nil, &corev1.PodList{})
+	sts := workerStatefulSet()
+	c := &types.ComponentInfo{Name: sts.Name, StatefulSet: sts}
+
+	require.NoError(t, m.mapPods(context.Background(), c, "default"))
+	assert.Nil(t, c.Pods, "pods unknown, not none")
+	assert.Equal(t, []string{"alluxio/alluxio:2.9.0"}, c.Images, "images fall back to the pod template")
+}
+
+func TestMapPods_SelectsWorkloadPods(t *testing.T) {
+	pod := func(name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "worker", Image: "alluxio/alluxio:2.9.1"}}},
+		}
+	}
+	m := fakeMapper(t, []client.Object{
+		pod("demo-worker-1", map[string]string{"role": "alluxio-worker"}),
+		pod("demo-worker-0", map[string]string{"role": "alluxio-worker"}),
+		pod("demo-master-0", map[string]string{"role": "alluxio-master"}),
+	})
+	sts := workerStatefulSet()
+	c := &types.ComponentInfo{Name: sts.Name, StatefulSet: sts}
+
+	require.NoError(t, m.mapPods(context.Background(), c, "default"))
+	require.Len(t, c.Pods, 2)
+	assert.Equal(t, "demo-worker-0", c.Pods[0].Name)
+	assert.Equal(t, "demo-worker-1", c.Pods[1].Name)
+	assert.Equal(t, []string{"alluxio/alluxio:2.9.1"}, c.Images)
+}
+
+func TestMapConsumers(t *testing.T) {
+	mounting := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "trainer", Namespace: "default"},
+		Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+			Name:         "data",
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "demo"}},
+		}}},
+	}
+	other := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+
+	consumers := mapConsumers("demo", []corev1.Pod{*mounting, *other})
+	require.Len(t, consumers, 1)
+	assert.Equal(t, "trainer", consumers[0].Name)
+
+	consumers = mapConsumers("demo", []corev1.Pod{*other})
+	assert.NotNil(t, consumers, "no consumers, but known")
+	assert.Empty(t, consumers)
+
+	// Without permission the consumers are unknown, so FUSE_MISSING falls back to the fuse pods.
+	scope, err := fakeMapper(t, []client.Object{mounting}, &corev1.PodList{}).mapNamespaceScope(context.Background(), "default")
+	require.NoError(t, err)
+	assert.Nil(t, mapConsumers("demo", scope.pods))
+
+	scope, err = fakeMapper(t, nil).mapNamespaceScope(context.Background(), "default")
+	require.NoError(t, err)
+	assert.NotNil(t, mapConsumers("demo", scope.pods), "an empty namespace has no consumers, but known")
+}
+
+// fluidObject builds a Fluid custom resource from its spec.
+func fluidObject(kind, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	u.SetGroupVersionKind(schema.GroupVersionKind{Group: "data.fluid.io", Version: "v1alpha1", Kind: kind})
+	u.SetName(name)
+	u.SetNamespace("default")
+	return u
+}
+
+func TestMapDataset_ForbiddenPodsAndEvents(t *testing.T) {
+	m := fakeMapper(t, []client.Object{
+		fluidObject("Dataset", "demo", map[string]interface{}{}),
+		fluidObject("AlluxioRuntime", "demo", map[string]interface{}{"replicas": int64(2)}),
+		workerStatefulSet(),
+	}, &corev1.PodList{}, &corev1.EventList{})
+
+	g, err := m.MapDataset(context.Background(), "demo", "default")
+	require.NoError(t, err)
+	require.NotNil(t, g.Runtime)
+	require.NotNil(t, g.Runtime.Worker)
+	assert.Nil(t, g.Runtime.Worker.Pods)
+	assert.Nil(t, g.Consumers)
+	assert.Nil(t, g.Events)
+}
+
+func TestMapAll_ListsNamespaceOnce(t *testing.T) {
+	dataset := func(name, namespace string) *unstructured.Unstructured {
+		u := fluidObject("Dataset", name, map[string]interface{}{})
+		u.SetNamespace(namespace)
+		return u
+	}
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "b.1", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Dataset", Name: "b"},
+		Reason:         "Failed",
+	}
+	m := fakeMapper(t, []client.Object{
+		dataset("a", "default"), dataset("b", "default"), dataset("c", "default"), dataset("d", "team"), event,
+	})
+	lists := map[string]int{}
+	inner := m.client
+	m.client = countingClient{Client: inner, lists: lists}
+
+	graphs, err := m.MapAll(context.Background(), "")
+	require.NoError(t, err)
+	require.Len(t, graphs, 4)
+	assert.Equal(t, 2, lists["*v1.PodList"], "pods are listed once per namespace")
+	assert.Equal(t, 2, lists["*v1.EventList"], "events are listed once per namespace")
+	assert.Empty(t, graphs[0].Events)
+	require.Len(t, graphs[1].Events, 1, "events are still filtered per dataset")
+	assert.Equal(t, "b", graphs[1].Events[0].Name)
+}
+
+// countingClient counts the List calls per list type.
+type countingClient struct {
+	client.Client
+	lists map[string]int
+}
+
+func (c countingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	c.lists[fmt.Sprintf("%T", list)]++
+	return c.Client.List(ctx, list, opts...)
+}
+
+func TestDesiredSpec(t *testing.T) {
+	int32p := func(n int32) *int32 { return &n }
+	cases := []struct {
+		name, kind, role string
+		spec             map[string]interface{}
+		want             *types.ComponentSpec
+	}{
+		{
+			name: "alluxio master", kind: "AlluxioRuntime", role: "master",
+			spec: map[string]interface{}{
+				"master":         map[string]interface{}{"replicas": int64(3), "resources": map[string]interface{}{"requests": map[string]interface{}{"memory": "4Gi"}}},
+				"alluxioVersion": map[string]interface{}{"image": "alluxio/alluxio", "imageTag": "2.9.0"},
+			},
+			want: &types.ComponentSpec{Replicas: int32p(3), Image: "alluxio/alluxio", ImageTag: "2.9.0",
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")}},
+		},
+		{
+			// Decoded from JSON, numbers are float64.
+			name: "jindo workers by spec.replicas", kind: "JindoRuntime", role: "worker",
+			spec: map[string]interface{}{"replicas": float64(2), "worker": map[string]interface{}{"replicas": float64(5)},
+				"jindoVersion": map[string]interface{}{"imageTag": "6.2.0"}},
+			want: &types.ComponentSpec{Replicas: int32p(2), ImageTag: "6.2.0"},
+		},
+		{
+			name: "workers by spec.worker.replicas", kind: "JuiceFSRuntime", role: "worker",
+			spec: map[string]interface{}{"worker": map[string]interface{}{"replicas": int64(4)}},
+			want: &types.ComponentSpec{Replicas: int32p(4)},
+		},
+		{
+			name: "fuse image set separately", kind: "AlluxioRuntime", role: "fuse",
+			spec: map[string]interface{}{"fuse": map[string]interface{}{"image": "alluxio/alluxio-fuse", "imageTag": "2.9.0"},
+				"alluxioVersion": map[string]interface{}{"image": "alluxio/alluxio", "imageTag": "2.8.0"}},
+			want: &types.ComponentSpec{Image: "alluxio/alluxio-fuse", ImageTag: "2.9.0"},
+		},
+		{
+			name: "no version key", kind: "ThinRuntime", role: "worker",
+			spec: map[string]interface{}{"replicas": "two", "worker": map[string]interface{}{"resources": map[string]interface{}{"requests": map[string]interface{}{"cpu": "lots"}}}},
+			want: &types.ComponentSpec{},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, desiredSpec(fluidObject(tc.kind, "demo", tc.spec), tc.kind, tc.role))
+		})
+	}
+}
+
+func TestActualSpec(t *testing.T) {
+	sts := workerStatefulSet()
+	sts.Spec.Template.Spec.Containers = append(sts.Spec.Template.Spec.Containers, corev1.Container{Name: "sidecar", Image: "busybox:1.36"})
+	sts.Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}
+	ds := &appsv1.DaemonSet{Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "fuse", Image: "registry.local:5000/alluxio/alluxio-fuse@sha256:abc"}},
+	}}}}
+
+	cases := []struct {
+		name string
+		c    *types.ComponentInfo
+		want *types.ComponentSpec
+	}{
+		{"statefulset main container", &types.ComponentInfo{StatefulSet: sts},
+			&types.ComponentSpec{Replicas: sts.Spec.Replicas, Image: "alluxio/alluxio", ImageTag: "2.9.0",
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}}},
+		{"daemonset has no replicas", &types.ComponentInfo{DaemonSet: ds},
+			&types.ComponentSpec{Image: "registry.local:5000/alluxio/alluxio-fuse", ImageTag: "sha256:abc"}},
+		{"no workload", &types.ComponentInfo{}, &types.ComponentSpec{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, actualSpec(tc.c))
+		})
+	}
+}
+
+func TestNestedInt32(t *testing.T) {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{
+		"int":    int64(3),
+		"float":  float64(2),
+		"string": "3",
+	}}}
+	cases := []struct {
+		field string
+		want  *int32
+	}{
+		{"int", func() *int32 { n := int32(3); return &n }()},
+		{"float", func() *int32 { n := int32(2); return &n }()},
+		{"string", nil},
+		{"missing", nil},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, nestedInt32(u, "spec", tc.field), tc.field)
+	}
+	assert.Nil(t, nestedInt32(u, "spec", "int", "deeper"), "not a map")
+}
+
+func TestMapControllers(t *testing.T) {
+	replicas := int32(2)
+	deploy := func(name, image string, replicas *int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: FluidNamespace},
+			Spec: appsv1.DeploymentSpec{Replicas: replicas, Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "manager", Image: image}},
+			}}},
+			Status: appsv1.DeploymentStatus{ReadyReplicas: 1},
+		}
+	}
+	csi := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "csi-nodeplugin-fluid", Namespace: FluidNamespace},
+		Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "registrar", Image: "registrar:v2"}, {Name: "plugins", Image: "fluid/csi:v1.0.0"}},
+		}}},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3},
+	}
+	elsewhere := deploy("dataset-controller", "fluid/dataset-controller:v0.9.0", nil)
+	elsewhere.Namespace = "default"
+
+	controllers, err := fakeMapper(t, []client.Object{
+		deploy("dataset-controller", "fluid/dataset-controller:v1.0.0", nil),
+		deploy("alluxioruntime-controller", "fluid/alluxioruntime-controller:v1.0.0", &replicas),
+		csi, elsewhere,
+	}).mapControllers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []types.ControllerInfo{
+		{Name: "alluxioruntime-controller", Kind: "Deployment", Images: []string{"fluid/alluxioruntime-controller:v1.0.0"}, Replicas: 2, Ready: 1},
+		{Name: "csi-nodeplugin-fluid", Kind: "DaemonSet", Images: []string{"registrar:v2", "fluid/csi:v1.0.0"}, Replicas: 3, Ready: 3},
+		{Name: "dataset-controller", Kind: "Deployment", Images: []string{"fluid/dataset-controller:v1.0.0"}, Replicas: 1, Ready: 1},
+	}, controllers, "sorted by name; unset replicas default to one")
+
+	controllers, err = fakeMapper(t, []client.Object{csi}, &appsv1.DeploymentList{}).mapControllers(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, controllers, "not readable with namespace-scoped permissions")
+}
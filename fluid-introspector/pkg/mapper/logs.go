@@ -0,0 +1,74 @@
+package mapper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultLogTailLines is how many of the latest lines SamplePodLogs reads per container.
+const DefaultLogTailLines int64 = 200
+
+// maxLogBytes bounds the log read per container, whatever its line length.
+const maxLogBytes int64 = 256 * 1024
+
+// SamplePodLogs reads the last tailLines lines of every container of the runtime pods
+// of g, keyed by pod name as in DiagnosticContext.PodLogs, with the containers' logs
+// of a pod concatenated. Containers whose logs cannot be read, e.g. not started yet,
+// are left out. Without permission to read pod logs it returns nil.
+func SamplePodLogs(ctx context.Context, cs kubernetes.Interface, g *types.ResourceGraph, tailLines int64) (map[string]string, error) {
+	if g.Runtime == nil {
+		return nil, nil
+	}
+	logs := make(map[string]string)
+	for _, c := range []*types.ComponentInfo{g.Runtime.Master, g.Runtime.Worker, g.Runtime.Fuse} {
+		if c == nil {
+			continue
+		}
+		for _, p := range c.Pods {
+			var b strings.Builder
+			for _, ctr := range p.Containers {
+				text, err := containerLog(ctx, cs, g.Dataset.Namespace, p.Name, ctr.Name, tailLines)
+				switch {
+				case apierrors.IsForbidden(err):
+					return nil, nil
+				case err != nil:
+					continue
+				}
+				b.WriteString(text)
+				if !strings.HasSuffix(text, "\n") {
+					b.WriteString("\n")
+				}
+			}
+			if b.Len() > 0 {
+				logs[p.Name] = b.String()
+			}
+		}
+	}
+	return logs, nil
+}
+
+func containerLog(ctx context.Context, cs kubernetes.Interface, namespace, pod, container string, tailLines int64) (string, error) {
+	limit := maxLogBytes
+	req := cs.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container:  container,
+		TailLines:  &tailLines,
+		LimitBytes: &limit,
+	})
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+	data, err := io.ReadAll(stream)
+	if err != nil {
+		return "", fmt.Errorf("failed to read logs of %s/%s: %w", pod, container, err)
+	}
+	return string(data), nil
+}
@@ -27,7 +27,7 @@ func (m *ResourceMapper) discoverRuntime(ctx context.Context, dataset *types.Dat
 			Ready:    1,
 			State:    "Ready",
 			Pods: []types.PodInfo{
-				{Name: dataset.Name + "-master-0", Status: "Running", Age: "10m"},
+				{Name: dataset.Name + "-master-0", Status: "Running", Ready: true, Age: "10m"},
 			},
 		},
 		Worker: &types.ComponentInfo{
@@ -36,8 +36,8 @@ func (m *ResourceMapper) discoverRuntime(ctx context.Context, dataset *types.Dat
 			Ready:    2,
 			State:    "PartialReady",
 			Pods: []types.PodInfo{
-				{Name: dataset.Name + "-worker-0", Status: "Running", Age: "10m"},
-				{Name: dataset.Name + "-worker-1", Status: "Running", Age: "10m"},
+				{Name: dataset.Name + "-worker-0", Status: "Running", Ready: true, Age: "10m"},
+				{Name: dataset.Name + "-worker-1", Status: "Running", Ready: true, Age: "10m"},
 				{Name: dataset.Name + "-worker-2", Status: "CrashLoopBackOff", Age: "5m", Restarts: 5},
 			},
 		},
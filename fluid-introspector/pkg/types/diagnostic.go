@@ -6,21 +6,90 @@ import (
 
 // DiagnosticResult encapsulates the overall health assessment and diagnosis.
 type DiagnosticResult struct {
-	Timestamp     time.Time      `json:"timestamp"`               // Time of diagnosis
-	IsHealthy     bool           `json:"isHealthy"`               // High-level health indicator
-	Summary       string         `json:"summary"`                 // Brief sentence like "Dataset is Bound but Runtime partially unready."
-	FailureHints  []FailureHint  `json:"failureHints"`            // Specific findings
-	ResourceGraph *ResourceGraph `json:"resourceGraph,omitempty"` // Context
+	Timestamp     time.Time         `json:"timestamp"`               // Time of diagnosis
+	IsHealthy     bool              `json:"isHealthy"`               // High-level health indicator
+	Score         int               `json:"score"`                   // Weighted health from 0 (down) to 100 (healthy)
+	Components    []ComponentHealth `json:"components"`              // Health per component, in fixed order
+	Summary       string            `json:"summary"`                 // Brief sentence like "Dataset is Bound but Runtime partially unready."
+	Fingerprint   string            `json:"fingerprint"`             // Hash of the active problems; see diagnose.Fingerprint
+	FailureHints  []FailureHint     `json:"failureHints"`            // Specific findings
+	Silenced      []SilencedHint    `json:"silenced,omitempty"`      // Findings acknowledged through the Dataset's silence annotation
+	ResourceGraph *ResourceGraph    `json:"resourceGraph,omitempty"` // Context
+	Profile       string            `json:"profile,omitempty"`       // Name of the rule configuration profile in effect
+	Trace         []RuleTrace       `json:"trace,omitempty"`         // Every rule of the run and its outcome; see diagnose.WithTrace
 }
 
+// RuleTrace explains the outcome of one rule of a run: what it read from the graph
+// and why it fired, passed or was skipped.
+type RuleTrace struct {
+	RuleID   string       `json:"ruleId"`
+	Outcome  TraceOutcome `json:"outcome"`
+	Reason   string       `json:"reason"`
+	Inputs   []Fact       `json:"inputs,omitempty"`   // The graph values the rule reads, as observed
+	Findings int          `json:"findings,omitempty"` // Findings reported, silenced ones included
+	Silenced int          `json:"silenced,omitempty"` // Findings moved aside by the Dataset's silences
+}
+
+type TraceOutcome string
+
+const (
+	TraceFired   TraceOutcome = "Fired"   // The rule reported findings
+	TracePassed  TraceOutcome = "Passed"  // The rule ran and found nothing
+	TraceSkipped TraceOutcome = "Skipped" // The rule did not apply, e.g. to another runtime
+	TraceError   TraceOutcome = "Error"   // The rule panicked
+)
+
+// ComponentHealth summarizes the active findings of one component of a Dataset.
+type ComponentHealth struct {
+	Name     string       `json:"name"` // dataset, master, worker, fuse, storage or operations
+	Status   HealthStatus `json:"status"`
+	Score    int          `json:"score"`              // 0 to 100
+	Findings int          `json:"findings,omitempty"` // Active Warning and Critical findings attributed to the component
+}
+
+type HealthStatus string
+
+const (
+	HealthHealthy   HealthStatus = "Healthy"
+	HealthDegraded  HealthStatus = "Degraded"  // Warning findings
+	HealthUnhealthy HealthStatus = "Unhealthy" // Critical findings
+)
+
 // FailureHint describes a detected issue with severity and remediation suggestions.
 type FailureHint struct {
-	ID         string        `json:"id"`                // Unique identifier for the rule (e.g., DATASET_NOT_BOUND)
-	Severity   SeverityLevel `json:"severity"`          // E.g., CRITICAL, WARNING, INFO
-	Component  string        `json:"component"`         // E.g., "Worker", "PVC"
-	Evidence   Evidence      `json:"evidence"`          // Concrete data supporting the finding
-	Suggestion string        `json:"suggestion"`        // Actionable step for the user
-	Context    string        `json:"context,omitempty"` // Additional explanation
+	ID          string            `json:"id"`                    // Unique identifier for the rule (e.g., DATASET_NOT_BOUND)
+	Severity    SeverityLevel     `json:"severity"`              // E.g., CRITICAL, WARNING, INFO
+	Component   string            `json:"component"`             // E.g., "Worker", "PVC"
+	Evidence    Evidence          `json:"evidence"`              // Concrete data supporting the finding
+	Suggestion  string            `json:"suggestion"`            // Actionable step for the user
+	Remediation []RemediationStep `json:"remediation,omitempty"` // Ordered steps with pre-filled commands and patches
+	Context     string            `json:"context,omitempty"`     // Additional explanation
+	RootCause   bool              `json:"rootCause,omitempty"`   // No other finding explains this one
+	CausedBy    []string          `json:"causedBy,omitempty"`    // IDs of findings this one is a consequence of
+}
+
+// RemediationStep is one step of a finding's remediation plan. Commands only read
+// from the cluster; patches are printed for review and never applied.
+type RemediationStep struct {
+	Description string `json:"description"`
+	Command     string `json:"command,omitempty"` // Read-only kubectl command, e.g. "kubectl describe pod demo-data-worker-2 -n default"
+	Patch       *Patch `json:"patch,omitempty"`
+}
+
+// Patch is a suggested merge patch, in YAML, for a Runtime or Dataset.
+type Patch struct {
+	Kind      string `json:"kind"` // e.g. AlluxioRuntime
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	YAML      string `json:"yaml"`
+}
+
+// SilencedHint is a finding suppressed by a Silence. It is kept in the result so
+// reports stay honest about what was acknowledged and why.
+type SilencedHint struct {
+	FailureHint
+	Reason  string    `json:"reason,omitempty"`
+	Expires time.Time `json:"expires,omitzero"`
 }
 
 type SeverityLevel string
@@ -31,12 +100,33 @@ const (
 	SeverityInfo     SeverityLevel = "Info"
 )
 
-// Evidence provides structured data linking a diagnosis to a resource.
+// Evidence provides structured data linking a diagnosis to resources. Kind and Name
+// name the primary object, which the engine lists first in Objects.
 type Evidence struct {
-	Kind   string   `json:"kind"`           // e.g., Pod, Dataset
-	Name   string   `json:"name"`           // e.g., runtime-master-0
-	Detail string   `json:"detail"`         // e.g., "ExitCode 137, OOMKilled"
-	Logs   []string `json:"logs,omitempty"` // Relevant log snippet
+	Kind    string      `json:"kind"`              // e.g., Pod, Dataset
+	Name    string      `json:"name"`              // e.g., runtime-master-0
+	Detail  string      `json:"detail"`            // e.g., "ExitCode 137, OOMKilled"; rendered from Facts if a rule sets none
+	Objects []ObjectRef `json:"objects,omitempty"` // Every object the finding is about, the primary one first
+	Facts   []Fact      `json:"facts,omitempty"`   // Observed values behind Detail, e.g. readyReplicas=2
+	Logs    []string    `json:"logs,omitempty"`    // Relevant log snippet
+}
+
+// ObjectRef identifies a Kubernetes object. UID and ResourceVersion pin the exact
+// object observed; they are empty when the graph does not carry the object, as in
+// mock mode.
+type ObjectRef struct {
+	APIVersion      string `json:"apiVersion"` // e.g., apps/v1
+	Kind            string `json:"kind"`
+	Namespace       string `json:"namespace,omitempty"` // Empty for cluster-scoped objects
+	Name            string `json:"name"`
+	UID             string `json:"uid,omitempty"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+// Fact is one observed value behind a finding, in the order the rule reports them.
+type Fact struct {
+	Key   string `json:"key"` // camelCase, e.g. readyReplicas
+	Value string `json:"value"`
 }
 
 // DiagnosticContext is a holder for data passed between pipeline stages or for AI consumption.
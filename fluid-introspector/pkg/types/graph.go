@@ -1,61 +1,185 @@
 package types
 
 import (
+	"time"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ResourceGraph represents the hierarchical structure of a Fluid Dataset and its related resources.
 type ResourceGraph struct {
-	Dataset     *DatasetInfo     `json:"dataset"`
-	Runtime     *RuntimeInfo     `json:"runtime,omitempty"`
+	Dataset        *DatasetInfo        `json:"dataset"`
+	Runtime        *RuntimeInfo        `json:"runtime,omitempty"`
 	Infrastructure *InfrastructureInfo `json:"infrastructure,omitempty"`
+	Nodes          []NodeInfo          `json:"nodes,omitempty"`       // Cluster node inventory, if readable
+	Events         []EventInfo         `json:"events,omitempty"`      // Events about objects of the graph, oldest first
+	Controllers    []ControllerInfo    `json:"controllers,omitempty"` // Fluid controllers, if readable
+	Consumers      []PodInfo           `json:"consumers,omitempty"`   // Application pods mounting the Dataset\'s PVC; nil if not collected
+	Secrets        []SecretInfo        `json:"secrets,omitempty"`     // Secrets referenced by the Dataset's encryptOptions; nil if not collected
+	ObservedAt     time.Time           `json:"observedAt,omitzero"`   // When the snapshot was taken; resource ages are measured against it
 }
 
 // DatasetInfo encapsulates details about the Dataset CR.
 type DatasetInfo struct {
-	Name      string            `json:"name"`
-	Namespace string            `json:"namespace"`
-	Status    string            `json:"status"`
-	Phase     string            `json:"phase"`
-	Reason    string            `json:"reason,omitempty"`
-	Labels    map[string]string `json:"labels,omitempty"`
-	Object    metav1.Object     `json:"-"` // Raw object for internal use
+	Name               string            `json:"name"`
+	Namespace          string            `json:"namespace"`
+	Status             string            `json:"status"`
+	Phase              string            `json:"phase"`
+	Reason             string            `json:"reason,omitempty"`
+	Labels             map[string]string `json:"labels,omitempty"`
+	CreationTimestamp  time.Time         `json:"creationTimestamp,omitzero"`
+	LastTransitionTime time.Time         `json:"lastTransitionTime,omitzero"` // Latest status condition transition
+	Silences           []Silence         `json:"silences,omitempty"`          // Parsed from the SilenceAnnotation
+	SilenceError       string            `json:"silenceError,omitempty"`      // Set if the SilenceAnnotation could not be parsed
+	Mounts             []MountInfo       `json:"mounts,omitempty"`            // spec.mounts, with sharedOptions and sharedEncryptOptions merged in
+	Object             metav1.Object     `json:"-"`                           // Raw object for internal use
 }
 
 // RuntimeInfo encapsulates details about the Runtime CR (Alluxio, Jindo, JuiceFS, etc.).
 type RuntimeInfo struct {
-	Name      string            `json:"name"`
-	Type      string            `json:"type"` // e.g., AlluxioRuntime, JindoRuntime
-	Phase     string            `json:"phase"`
-	Master    *ComponentInfo    `json:"master,omitempty"`
-	Worker    *ComponentInfo    `json:"worker,omitempty"`
-	Fuse      *ComponentInfo    `json:"fuse,omitempty"`
-	Configs   []ConfigInfo      `json:"configs,omitempty"`
-	Object    metav1.Object     `json:"-"`
+	Name               string             `json:"name"`
+	Type               string             `json:"type"` // e.g., AlluxioRuntime, JindoRuntime
+	Phase              string             `json:"phase"`
+	Master             *ComponentInfo     `json:"master,omitempty"`
+	Worker             *ComponentInfo     `json:"worker,omitempty"`
+	Fuse               *ComponentInfo     `json:"fuse,omitempty"`
+	Configs            []ConfigInfo       `json:"configs,omitempty"`
+	TieredStore        []TieredStoreLevel `json:"tieredStore,omitempty"`  // spec.tieredstore.levels
+	ProfileName        string             `json:"profileName,omitempty"`  // ThinRuntime spec.profileName
+	ProfileFound       *bool              `json:"profileFound,omitempty"` // Whether the ThinRuntimeProfile exists; nil if not checked
+	FuseImage          string             `json:"fuseImage,omitempty"`    // ThinRuntime spec.fuse.image, else the ThinRuntimeProfile's; empty if neither sets one
+	CreationTimestamp  time.Time          `json:"creationTimestamp,omitzero"`
+	LastTransitionTime time.Time          `json:"lastTransitionTime,omitzero"` // Latest status condition transition
+	Object             metav1.Object      `json:"-"`
+}
+
+// MountInfo is one mount of a Dataset. Option values may hold credentials, so only
+// their keys are collected; encrypt options name the Secret key holding the value.
+type MountInfo struct {
+	Name           string          `json:"name,omitempty"`
+	MountPoint     string          `json:"mountPoint"` // e.g. oss://bucket/path, juicefs:///
+	OptionKeys     []string        `json:"optionKeys,omitempty"`
+	EncryptOptions []EncryptOption `json:"encryptOptions,omitempty"`
+}
+
+// EncryptOption is a mount option whose value is read from a Secret key.
+type EncryptOption struct {
+	Name       string `json:"name"` // e.g. metaurl, fs.oss.accessKeySecret
+	SecretName string `json:"secretName"`
+	SecretKey  string `json:"secretKey"`
+}
+
+// SecretInfo records whether a referenced Secret exists and its key names. Values
+// are never collected.
+type SecretInfo struct {
+	Name  string   `json:"name"`
+	Found bool     `json:"found"`
+	Keys  []string `json:"keys,omitempty"` // Sorted
+}
+
+// TieredStoreLevel is one cache level of a Runtime's tiered store. Every worker
+// caches up to Quota on the level's paths.
+type TieredStoreLevel struct {
+	MediumType string            `json:"mediumType"` // MEM, SSD or HDD
+	Paths      []string          `json:"paths"`
+	Quota      resource.Quantity `json:"quota"` // quota, or the sum of quotaList
 }
 
 // ComponentInfo represents a specific runtime component (Master, Worker, Fuse).
 type ComponentInfo struct {
-	Name        string             `json:"name"`
-	Replicas    int32              `json:"replicas"`
-	Ready       int32              `json:"ready"`
-	State       string             `json:"state"` // e.g., "PartialReady", "Ready"
-	Pods        []PodInfo          `json:"pods,omitempty"`
-	DaemonSet   *appsv1.DaemonSet  `json:"-"`
-	StatefulSet *appsv1.StatefulSet `json:"-"`
+	Name               string              `json:"name"`
+	Replicas           int32               `json:"replicas"`
+	Ready              int32               `json:"ready"`
+	State              string              `json:"state"` // e.g., "PartialReady", "Ready"
+	Pods               []PodInfo           `json:"pods,omitempty"`
+	Images             []string            `json:"images,omitempty"`            // Distinct app container images of the pods
+	NodeSelector       map[string]string   `json:"nodeSelector,omitempty"`      // Of the pod template; fluid.io/f-<namespace>-<name> marks an on-demand fuse
+	Desired            *ComponentSpec      `json:"desired,omitempty"`           // As declared in the Runtime spec
+	Actual             *ComponentSpec      `json:"actual,omitempty"`            // As found in the StatefulSet/DaemonSet pod template
+	CreationTimestamp  time.Time           `json:"creationTimestamp,omitzero"`  // Creation of the StatefulSet/DaemonSet
+	LastTransitionTime time.Time           `json:"lastTransitionTime,omitzero"` // Latest readiness transition among its pods
+	DaemonSet          *appsv1.DaemonSet   `json:"-"`
+	StatefulSet        *appsv1.StatefulSet `json:"-"`
+}
+
+// ComponentSpec is the part of a component's specification that the Runtime
+// controller propagates to its workload. Empty fields are not declared.
+type ComponentSpec struct {
+	Replicas *int32              `json:"replicas,omitempty"`
+	Image    string              `json:"image,omitempty"`    // Repository, without tag
+	ImageTag string              `json:"imageTag,omitempty"` // Tag or digest
+	Requests corev1.ResourceList `json:"requests,omitempty"` // Of the main container
 }
 
 // PodInfo represents a single pod within a component.
 type PodInfo struct {
-	Name         string            `json:"name"`
-	Status       string            `json:"status"` // e.g., Running, Pending
-	Node         string            `json:"node,omitempty"`
-	Restarts     int32             `json:"restarts"`
-	Age          string            `json:"age"`
-	LastState    *corev1.ContainerState `json:"lastState,omitempty"`
-	Object       *corev1.Pod       `json:"-"`
+	Name               string                 `json:"name"`
+	Status             string                 `json:"status"` // e.g., Running, Pending
+	Ready              bool                   `json:"ready"`  // PodReady condition
+	Phase              string                 `json:"phase,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`        // Pod status reason, e.g., Evicted
+	Message            string                 `json:"message,omitempty"`       // Pod status message
+	Unschedulable      string                 `json:"unschedulable,omitempty"` // Scheduler message if the PodScheduled condition is False
+	NodeSelector       map[string]string      `json:"nodeSelector,omitempty"`
+	Requests           corev1.ResourceList    `json:"requests,omitempty"`  // Summed over app containers
+	HostPorts          []int32                `json:"hostPorts,omitempty"` // Ports bound on the node, including container ports under hostNetwork
+	HostPaths          []string               `json:"hostPaths,omitempty"` // Paths of hostPath volumes, e.g. tiered store directories
+	Node               string                 `json:"node,omitempty"`
+	Restarts           int32                  `json:"restarts"`
+	Age                string                 `json:"age"`
+	LastState          *corev1.ContainerState `json:"lastState,omitempty"`
+	Containers         []ContainerInfo        `json:"containers,omitempty"` // Init containers first, then app containers
+	CreationTimestamp  time.Time              `json:"creationTimestamp,omitzero"`
+	LastTransitionTime time.Time              `json:"lastTransitionTime,omitzero"` // Transition time of the Ready condition
+	Object             *corev1.Pod            `json:"-"`
+}
+
+// ContainerInfo captures the spec and status of a single container of a pod.
+type ContainerInfo struct {
+	Name         string                 `json:"name"`
+	Image        string                 `json:"image,omitempty"`
+	Init         bool                   `json:"init,omitempty"` // Init container
+	Ready        bool                   `json:"ready"`
+	RestartCount int32                  `json:"restartCount"`
+	MemoryLimit  string                 `json:"memoryLimit,omitempty"` // e.g., 4Gi; empty if unlimited
+	State        *corev1.ContainerState `json:"state,omitempty"`
+	LastState    *corev1.ContainerState `json:"lastState,omitempty"` // Last termination state
+}
+
+// NodeInfo summarizes a cluster node for scheduling analysis.
+type NodeInfo struct {
+	Name          string              `json:"name"`
+	Ready         bool                `json:"ready"`
+	Unschedulable bool                `json:"unschedulable,omitempty"` // Cordoned
+	Labels        map[string]string   `json:"labels,omitempty"`
+	Taints        []corev1.Taint      `json:"taints,omitempty"`
+	Allocatable   corev1.ResourceList `json:"allocatable,omitempty"`
+	Pressures     []string            `json:"pressures,omitempty"` // True pressure conditions, e.g. DiskPressure
+	Object        *corev1.Node        `json:"-"`
+}
+
+// ControllerInfo is a workload of the Fluid installation, e.g. the dataset controller
+// or the CSI node plugin.
+type ControllerInfo struct {
+	Name     string   `json:"name"`
+	Kind     string   `json:"kind"`   // Deployment or DaemonSet
+	Images   []string `json:"images"` // Container images in pod template order
+	Replicas int32    `json:"replicas"`
+	Ready    int32    `json:"ready"`
+}
+
+// EventInfo is a Kubernetes event about an object of the graph.
+type EventInfo struct {
+	Kind          string    `json:"kind"` // Kind of the involved object
+	Name          string    `json:"name"` // Name of the involved object
+	Type          string    `json:"type"` // Normal or Warning
+	Reason        string    `json:"reason"`
+	Message       string    `json:"message"`
+	Count         int32     `json:"count,omitempty"`
+	LastTimestamp time.Time `json:"lastTimestamp,omitzero"`
 }
 
 // InfrastructureInfo groups underlying K8s storage resources.
@@ -65,14 +189,15 @@ type InfrastructureInfo struct {
 }
 
 type PVCInfo struct {
-	Name   string `json:"name"`
-	Status string `json:"status"` // e.g., Bound
-	Object *corev1.PersistentVolumeClaim `json:"-"`
+	Name              string                        `json:"name"`
+	Status            string                        `json:"status"` // e.g., Bound
+	CreationTimestamp time.Time                     `json:"creationTimestamp,omitzero"`
+	Object            *corev1.PersistentVolumeClaim `json:"-"`
 }
 
 type PVInfo struct {
-	Name   string `json:"name"`
-	Status string `json:"status"` // e.g., Bound
+	Name   string                   `json:"name"`
+	Status string                   `json:"status"` // e.g., Bound
 	Object *corev1.PersistentVolume `json:"-"`
 }
 
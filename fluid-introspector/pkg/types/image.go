@@ -0,0 +1,15 @@
+package types
+
+import "strings"
+
+// SplitImage splits an image reference into repository and tag or digest.
+// A port in the registry host is not mistaken for a tag.
+func SplitImage(ref string) (repository, tag string) {
+	if i := strings.LastIndex(ref, "@"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitImage(t *testing.T) {
+	cases := []struct {
+		ref, repository, tag string
+	}{
+		{"alluxio/alluxio:2.9.0", "alluxio/alluxio", "2.9.0"},
+		{"alluxio/alluxio", "alluxio/alluxio", ""},
+		{"alluxio", "alluxio", ""},
+		{"registry.local:5000/alluxio/alluxio", "registry.local:5000/alluxio/alluxio", ""},
+		{"registry.local:5000/alluxio/alluxio:2.9.0", "registry.local:5000/alluxio/alluxio", "2.9.0"},
+		{"alluxio/alluxio@sha256:0a1b2c", "alluxio/alluxio", "sha256:0a1b2c"},
+		{"registry.local:5000/alluxio/alluxio:2.9.0@sha256:0a1b2c", "registry.local:5000/alluxio/alluxio:2.9.0", "sha256:0a1b2c"},
+		{"", "", ""},
+	}
+	for _, tc := range cases {
+		repository, tag := SplitImage(tc.ref)
+		assert.Equal(t, tc.repository, repository, tc.ref)
+		assert.Equal(t, tc.tag, tag, tc.ref)
+	}
+}
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SilenceAnnotation is the Dataset annotation used to acknowledge known issues.
+// Its value is either a comma-separated list of rule IDs:
+//
+//	diagnose.fluid.io/silence: "FUSE_MISSING,WORKER_PARTIALLY_READY"
+//
+// or a JSON list with optional expiry and reason:
+//
+//	diagnose.fluid.io/silence: '[{"rule":"FUSE_MISSING","expires":"2026-12-01T00:00:00Z","reason":"node pool migration"}]'
+const SilenceAnnotation = "diagnose.fluid.io/silence"
+
+// Silence acknowledges the findings of one rule on a Dataset. Silenced findings
+// are reported separately instead of making the Dataset unhealthy.
+type Silence struct {
+	RuleID  string    `json:"rule"`
+	Expires time.Time `json:"expires,omitzero"` // Zero means the silence never expires
+	Reason  string    `json:"reason,omitempty"`
+}
+
+// ActiveAt reports whether the silence is in effect at the given time.
+func (s Silence) ActiveAt(t time.Time) bool {
+	return s.Expires.IsZero() || t.Before(s.Expires)
+}
+
+// ParseSilences parses the value of the SilenceAnnotation. An empty value yields no silences.
+func ParseSilences(value string) ([]Silence, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	var silences []Silence
+	if strings.HasPrefix(value, "[") {
+		if err := json.Unmarshal([]byte(value), &silences); err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %w", SilenceAnnotation, err)
+		}
+	} else {
+		for _, id := range strings.Split(value, ",") {
+			silences = append(silences, Silence{RuleID: strings.TrimSpace(id)})
+		}
+	}
+
+	for _, s := range silences {
+		if s.RuleID == "" {
+			return nil, fmt.Errorf("invalid %s annotation: empty rule ID", SilenceAnnotation)
+		}
+	}
+	return silences, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/diagnose"
 	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/k8s"
@@ -18,6 +19,10 @@ var (
 	inspectOutput    string
 	inspectMock      bool
 	inspectScenario  string
+	inspectRules     []string
+	inspectProfile   string
+	inspectLogs      bool
+	inspectExplain   bool
 )
 
 // inspectCmd represents the inspect command
@@ -32,11 +37,21 @@ var datasetCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		name := args[0]
+		rules, err := buildRuleSet(inspectRules)
+		if err != nil {
+			fmt.Printf("Error loading rules: %v\n", err)
+			os.Exit(1)
+		}
+		profile, err := loadProfile(inspectProfile, rules)
+		if err != nil {
+			fmt.Printf("Error loading profile: %v\n", err)
+			os.Exit(1)
+		}
 		if inspectMock {
-			runMock(name, inspectScenario, inspectOutput)
+			runMock(name, inspectScenario, inspectOutput, rules, profile)
 		} else {
 			// Real Mode Path
-			runReal(name, inspectNamespace, inspectOutput)
+			runReal(name, inspectNamespace, inspectOutput, rules, profile)
 		}
 	},
 }
@@ -50,15 +65,53 @@ func init() {
 	// It's safer to attach to PersistentFlags of inspectCmd if they apply to all inspects,
 	// or LocalFlags of datasetCmd. Let's put them on datasetCmd for now as requested.
 	datasetCmd.Flags().StringVarP(&inspectNamespace, "namespace", "n", "default", "Kubernetes namespace")
-	datasetCmd.Flags().StringVarP(&inspectOutput, "output", "o", "tree", "Output format: tree, json, wide")
+	datasetCmd.Flags().StringVarP(&inspectOutput, "output", "o", "tree", "Output format: tree, json, wide, fingerprint")
 	datasetCmd.Flags().BoolVar(&inspectMock, "mock", false, "Use mock data instead of live cluster")
-	datasetCmd.Flags().StringVar(&inspectScenario, "scenario", "healthy", "Mock scenario: healthy, partial-ready, missing-runtime, missing-fuse, failed-pods")
+	datasetCmd.Flags().StringVar(&inspectScenario, "scenario", "healthy", "Mock scenario: "+strings.Join(scenarios.Names(), ", "))
+	datasetCmd.Flags().StringSliceVar(&inspectRules, "rules-file", nil, "Declarative rule file or directory of *.yaml rule files (repeatable)")
+	datasetCmd.Flags().StringVar(&inspectProfile, "profile", "", "Rule configuration profile: disable rules, override severities and parameters")
+	datasetCmd.Flags().BoolVar(&inspectLogs, "logs", false, "Sample the latest logs of the runtime pods and match them against log signatures")
+	datasetCmd.Flags().BoolVar(&inspectExplain, "explain", false, "Trace every rule: the values it read and why it fired, passed or was skipped")
+}
+
+// buildRuleSet returns the built-in rules followed by the declarative rules loaded from paths.
+func buildRuleSet(paths []string) (diagnose.RuleSet, error) {
+	set, err := diagnose.DefaultRegistry.RuleSet()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		loaded, err := diagnose.LoadRules(path)
+		if err != nil {
+			return nil, err
+		}
+		if set, err = set.With(loaded...); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return set, nil
+}
+
+// loadProfile reads the profile at path, if any, and checks it against the rule set,
+// so that its errors are reported as such before any cluster work.
+func loadProfile(path string, rules diagnose.RuleSet) (*diagnose.Profile, error) {
+	if path == "" {
+		return nil, nil
+	}
+	profile, err := diagnose.LoadProfile(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := profile.Apply(rules); err != nil {
+		return nil, err
+	}
+	return profile, nil
 }
 
-func runMock(name, scenarioName, outputFormat string) {
+func runMock(name, scenarioName, outputFormat string, rules diagnose.RuleSet, profile *diagnose.Profile) {
 	s := scenarios.Get(scenarioName)
 	if s == nil {
-		fmt.Printf("Error: Scenario '%s' not found. Available: healthy, partial-ready, missing-runtime, missing-fuse, failed-pods\n", scenarioName)
+		fmt.Printf("Error: Scenario '%s' not found. Available: %s\n", scenarioName, strings.Join(scenarios.Names(), ", "))
 		os.Exit(1)
 	}
 
@@ -68,18 +121,36 @@ func runMock(name, scenarioName, outputFormat string) {
 	// For now, let's just use the scenario graph as is.
 
 	// Phase 2 Invoke: Diagnose
-	result := diagnose.Diagnose(s.Graph)
+	// Scenarios with logs behave as if sampled with --logs.
+	opts := []diagnose.Option{diagnose.WithRuleSet(rules), diagnose.WithProfile(profile), diagnose.WithClock(scenarios.Now), diagnose.WithPodLogs(s.PodLogs)}
+	if inspectExplain {
+		opts = append(opts, diagnose.WithTrace())
+	}
+	result, err := diagnose.Run(s.Graph, opts...)
+	if err != nil {
+		fmt.Printf("Error diagnosing dataset '%s': %v\n", name, err)
+		os.Exit(1)
+	}
 
 	// Phase 3 Invoke: Print
-	if outputFormat == "json" {
+	switch outputFormat {
+	case "json":
 		printer.PrintJSON(result)
-	} else {
+	case "fingerprint":
+		fmt.Println(result.Fingerprint)
+	case "wide":
 		fmt.Printf("[MOCK MODE] Scenario: %s\n", s.Description)
 		printer.PrintTree(result)
+		printer.PrintImages(result)
+		printer.PrintTrace(result)
+	default:
+		fmt.Printf("[MOCK MODE] Scenario: %s\n", s.Description)
+		printer.PrintTree(result)
+		printer.PrintTrace(result)
 	}
 }
 
-func runReal(name, namespace, outputFormat string) {
+func runReal(name, namespace, outputFormat string, rules diagnose.RuleSet, profile *diagnose.Profile) {
 	// 1. Initialize Client
 	cli, err := k8s.NewClient()
 	if err != nil {
@@ -98,13 +169,43 @@ func runReal(name, namespace, outputFormat string) {
 		os.Exit(1)
 	}
 
-	// 4. Diagnose
-	result := diagnose.Diagnose(graph)
+	// 4. Sample runtime pod logs for log signatures
+	var logs map[string]string
+	if inspectLogs {
+		cs, err := k8s.NewClientset()
+		if err != nil {
+			fmt.Printf("Error initializing K8s client: %v\n", err)
+			os.Exit(1)
+		}
+		if logs, err = mapper.SamplePodLogs(ctx, cs, graph, mapper.DefaultLogTailLines); err != nil {
+			fmt.Printf("Error sampling pod logs: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
-	// 5. Print
-	if outputFormat == "json" {
+	// 5. Diagnose
+	opts := []diagnose.Option{diagnose.WithRuleSet(rules), diagnose.WithProfile(profile), diagnose.WithContext(ctx), diagnose.WithPodLogs(logs)}
+	if inspectExplain {
+		opts = append(opts, diagnose.WithTrace())
+	}
+	result, err := diagnose.Run(graph, opts...)
+	if err != nil {
+		fmt.Printf("Error diagnosing dataset '%s' in namespace '%s': %v\n", name, namespace, err)
+		os.Exit(1)
+	}
+
+	// 6. Print
+	switch outputFormat {
+	case "json":
 		printer.PrintJSON(result)
-	} else {
+	case "fingerprint":
+		fmt.Println(result.Fingerprint)
+	case "wide":
+		printer.PrintTree(result)
+		printer.PrintImages(result)
+		printer.PrintTrace(result)
+	default:
 		printer.PrintTree(result)
+		printer.PrintTrace(result)
 	}
 }
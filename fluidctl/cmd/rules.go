@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/diagnose"
+	"github.com/fluid-cloudnative/fluid-introspector/fluidctl/pkg/printer"
+	"github.com/spf13/cobra"
+)
+
+var rulesOutput string
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Browse the diagnostic rule catalog",
+}
+
+var rulesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every diagnostic rule",
+	Long: `List the built-in rules, followed by any declarative rules loaded with --rules-file.
+-o markdown renders the rule table of the README.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		catalog := diagnose.Catalog(loadCatalogRules())
+		switch rulesOutput {
+		case "json":
+			printer.PrintRulesJSON(catalog)
+		case "markdown":
+			fmt.Print(diagnose.MarkdownTable(catalog))
+		default:
+			printer.PrintRules(catalog)
+		}
+	},
+}
+
+var rulesExplainCmd = &cobra.Command{
+	Use:   "explain <ID>",
+	Short: "Explain one diagnostic rule: what it checks, typical causes and remediation",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, rule := range loadCatalogRules() {
+			if rule.ID() != args[0] {
+				continue
+			}
+			if rulesOutput == "json" {
+				printer.PrintRulesJSON(diagnose.Describe(rule))
+			} else {
+				printer.PrintRuleExplain(diagnose.Describe(rule))
+			}
+			return
+		}
+		fmt.Printf("Error: rule '%s' not found. Run 'fluidctl rules list' for the available rules.\n", args[0])
+		os.Exit(1)
+	},
+}
+
+// loadCatalogRules returns the rule set the inspect commands would run.
+func loadCatalogRules() diagnose.RuleSet {
+	rules, err := buildRuleSet(inspectRules)
+	if err != nil {
+		fmt.Printf("Error loading rules: %v\n", err)
+		os.Exit(1)
+	}
+	return rules
+}
+
+func init() {
+	rootCmd.AddCommand(rulesCmd)
+	rulesCmd.AddCommand(rulesListCmd, rulesExplainCmd)
+
+	rulesListCmd.Flags().StringVarP(&rulesOutput, "output", "o", "tree", "Output format: tree, json, markdown")
+	rulesListCmd.Flags().StringSliceVar(&inspectRules, "rules-file", nil, "Declarative rule file or directory of *.yaml rule files (repeatable)")
+	rulesExplainCmd.Flags().StringVarP(&rulesOutput, "output", "o", "tree", "Output format: tree, json")
+	rulesExplainCmd.Flags().StringSliceVar(&inspectRules, "rules-file", nil, "Declarative rule file or directory of *.yaml rule files (repeatable)")
+}
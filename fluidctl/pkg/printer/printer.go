@@ -4,7 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/diagnose"
 	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
 )
 
@@ -17,26 +21,47 @@ func PrintTree(result *types.DiagnosticResult) {
 	} else {
 		fmt.Printf("❌ Dataset is Unhealthy\n")
 	}
-	fmt.Printf("Summary: %s\n\n", result.Summary)
+	if result.Profile != "" {
+		fmt.Printf("Profile: %s\n", result.Profile)
+	}
+	fmt.Printf("Summary: %s\n", result.Summary)
+	printHealth(result)
+	fmt.Println()
 
 	if len(result.FailureHints) > 0 {
-		fmt.Printf("FINDINGS:\n")
+		// Root causes are printed in full; the findings they explain are nested beneath them.
+		fmt.Printf("ROOT CAUSE:\n")
+		expanded := make(map[string]bool)
 		for _, hint := range result.FailureHints {
-			icon := "ℹ"
-			if hint.Severity == types.SeverityCritical {
-				icon = "❌"
-			} else if hint.Severity == types.SeverityWarning {
-				icon = "⚠"
+			if !hint.RootCause {
+				continue
+			}
+			printHint(hint)
+			if !expanded[hint.ID] {
+				expanded[hint.ID] = true
+				printConsequences(hint.ID, result.FailureHints)
 			}
-			fmt.Printf(" %s [%s] %s\n", icon, hint.Component, hint.ID)
-			fmt.Printf("    Evidence: %s (%s)\n", hint.Evidence.Detail, hint.Evidence.Name)
-			fmt.Printf("    Suggestion: %s\n\n", hint.Suggestion)
+			fmt.Println()
 		}
 	}
 
+	if len(result.Silenced) > 0 {
+		fmt.Printf("SILENCED:\n")
+		for _, s := range result.Silenced {
+			fmt.Printf(" 🔇 [%s] %s: %s (%s)\n", s.Component, s.ID, s.Evidence.Detail, evidenceObjects(s.Evidence))
+			if s.Reason != "" {
+				fmt.Printf("    Reason: %s\n", s.Reason)
+			}
+			if !s.Expires.IsZero() {
+				fmt.Printf("    Expires: %s\n", s.Expires.Format(time.RFC3339))
+			}
+		}
+		fmt.Println()
+	}
+
 	// Simple graph print (could be more elaborate)
 	g := result.ResourceGraph
-	if g == nil {
+	if g == nil || g.Dataset == nil {
 		return
 	}
 	fmt.Printf("RESOURCE GRAPH:\n")
@@ -49,6 +74,168 @@ func PrintTree(result *types.DiagnosticResult) {
 	} else {
 		fmt.Printf("└── Runtime: <Missing>\n")
 	}
+	if len(g.Consumers) > 0 {
+		fmt.Printf("Mounted by:\n")
+		for _, p := range g.Consumers {
+			node := p.Node
+			if node == "" {
+				node = "<unscheduled>"
+			}
+			fmt.Printf("    ├── Pod: %s (%s, %s)\n", p.Name, p.Status, node)
+		}
+	}
+}
+
+// printHealth prints the score and the status of each component.
+func printHealth(result *types.DiagnosticResult) {
+	if len(result.Components) == 0 {
+		return
+	}
+	parts := make([]string, 0, len(result.Components))
+	for _, c := range result.Components {
+		parts = append(parts, fmt.Sprintf("%s %s %d", c.Name, healthIcon(c.Status), c.Score))
+	}
+	fmt.Printf("Health score: %d/100 (%s)\n", result.Score, strings.Join(parts, ", "))
+	if result.Fingerprint != "" {
+		fmt.Printf("Fingerprint: %s\n", result.Fingerprint)
+	}
+}
+
+// evidenceObjects names the objects of the evidence kubectl-style, e.g.
+// pod/demo-data-worker-2, adding the namespace of objects outside the first
+// namespaced one's.
+func evidenceObjects(ev types.Evidence) string {
+	if len(ev.Objects) == 0 {
+		return ev.Name
+	}
+	namespace := ""
+	for _, o := range ev.Objects {
+		if o.Namespace != "" {
+			namespace = o.Namespace
+			break
+		}
+	}
+	names := make([]string, 0, len(ev.Objects))
+	for _, o := range ev.Objects {
+		name := strings.ToLower(o.Kind) + "/" + o.Name
+		if o.Namespace != "" && o.Namespace != namespace {
+			name += " -n " + o.Namespace
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func healthIcon(s types.HealthStatus) string {
+	switch s {
+	case types.HealthUnhealthy:
+		return "❌"
+	case types.HealthDegraded:
+		return "⚠"
+	default:
+		return "✓"
+	}
+}
+
+func printHint(hint types.FailureHint) {
+	fmt.Printf(" %s [%s] %s\n", severityIcon(hint.Severity), hint.Component, hint.ID)
+	fmt.Printf("    Evidence: %s (%s)\n", hint.Evidence.Detail, evidenceObjects(hint.Evidence))
+	for _, line := range hint.Evidence.Logs {
+		fmt.Printf("      | %s\n", line)
+	}
+	if hint.Context != "" {
+		fmt.Printf("    Context: %s\n", hint.Context)
+	}
+	fmt.Printf("    Suggestion: %s\n", hint.Suggestion)
+	printRemediation(hint.Remediation)
+}
+
+// printRemediation lists the steps of a plan. Patches are printed for review only.
+func printRemediation(steps []types.RemediationStep) {
+	if len(steps) == 0 {
+		return
+	}
+	fmt.Printf("    Remediation:\n")
+	for i, step := range steps {
+		fmt.Printf("      %d. %s\n", i+1, step.Description)
+		if step.Command != "" {
+			fmt.Printf("         $ %s\n", step.Command)
+		}
+		if p := step.Patch; p != nil {
+			target := p.Kind + "/" + p.Name
+			if p.Namespace != "" {
+				target += " -n " + p.Namespace
+			}
+			fmt.Printf("         Patch for %s (review, then apply with kubectl edit or your GitOps repo):\n", target)
+			for _, line := range strings.Split(strings.TrimRight(p.YAML, "\n"), "\n") {
+				fmt.Printf("           %s\n", line)
+			}
+		}
+	}
+}
+
+// printConsequences lists the findings explained, directly or transitively, by the root cause rootID.
+func printConsequences(rootID string, hints []types.FailureHint) {
+	var consequences []types.FailureHint
+	for _, h := range hints {
+		if !h.RootCause && explainedBy(h, rootID, hints) {
+			consequences = append(consequences, h)
+		}
+	}
+	if len(consequences) == 0 {
+		return
+	}
+
+	fmt.Printf("    Consequences:\n")
+	for i, h := range consequences {
+		branch := "├──"
+		if i == len(consequences)-1 {
+			branch = "└──"
+		}
+		fmt.Printf("    %s %s [%s] %s: %s (%s)\n", branch, severityIcon(h.Severity), h.Component, h.ID, h.Evidence.Detail, evidenceObjects(h.Evidence))
+	}
+}
+
+// explainedBy reports whether the finding h is explained by rootID, through its own
+// causedBy links: another finding of the same rule may have different causes.
+func explainedBy(h types.FailureHint, rootID string, hints []types.FailureHint) bool {
+	visited := map[string]bool{h.ID: true}
+	for _, cause := range h.CausedBy {
+		if cause == rootID || causedBy(cause, rootID, hints, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// causedBy reports whether findings with ID id are explained by rootID through the causedBy links.
+func causedBy(id, rootID string, hints []types.FailureHint, visited map[string]bool) bool {
+	if visited[id] {
+		return false
+	}
+	visited[id] = true
+	for _, h := range hints {
+		if h.ID != id {
+			continue
+		}
+		for _, cause := range h.CausedBy {
+			if cause == rootID || causedBy(cause, rootID, hints, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func severityIcon(s types.SeverityLevel) string {
+	switch s {
+	case types.SeverityCritical:
+		return "❌"
+	case types.SeverityWarning:
+		return "⚠"
+	default:
+		return "ℹ"
+	}
 }
 
 func printComponent(label string, c *types.ComponentInfo) {
@@ -63,6 +250,100 @@ func printComponent(label string, c *types.ComponentInfo) {
 		}
 	}
 	fmt.Printf("    ├── %s: %s %d/%d Ready\n", status, label, c.Ready, c.Replicas)
+	for _, p := range c.Pods {
+		podStatus := "✓"
+		if !p.Ready {
+			podStatus = "❌"
+		}
+		fmt.Printf("    │   ├── %s Pod: %s (%s)\n", podStatus, p.Name, p.Status)
+	}
+}
+
+// PrintImages renders the images of each runtime component and Fluid controller, with
+// the number of pods running each, for the wide output.
+func PrintImages(result *types.DiagnosticResult) {
+	g := result.ResourceGraph
+	if g == nil {
+		return
+	}
+	fmt.Printf("\nIMAGES:\n")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "COMPONENT\tNAME\tIMAGE\tPODS")
+	if g.Runtime != nil {
+		for _, c := range []struct {
+			label string
+			info  *types.ComponentInfo
+		}{{"Master", g.Runtime.Master}, {"Worker", g.Runtime.Worker}, {"Fuse", g.Runtime.Fuse}} {
+			if c.info == nil {
+				continue
+			}
+			for _, image := range c.info.Images {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\n", c.label, c.info.Name, image, podsRunning(c.info.Pods, image), len(c.info.Pods))
+			}
+		}
+	}
+	for _, c := range g.Controllers {
+		fmt.Fprintf(w, "Fluid (%s)\t%s\t%s\t%d/%d\n", c.Kind, c.Name, strings.Join(c.Images, ", "), c.Ready, c.Replicas)
+	}
+	w.Flush()
+}
+
+// podsRunning counts the pods with a container running image.
+func podsRunning(pods []types.PodInfo, image string) int {
+	n := 0
+	for _, p := range pods {
+		for _, c := range p.Containers {
+			if !c.Init && c.Image == image {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+// PrintTrace renders the rule trace of an explained run, one rule per line with the
+// values it read. It prints nothing for runs without a trace.
+func PrintTrace(result *types.DiagnosticResult) {
+	if len(result.Trace) == 0 {
+		return
+	}
+	counts := make(map[types.TraceOutcome]int)
+	for _, t := range result.Trace {
+		counts[t.Outcome]++
+	}
+	fmt.Printf("\nRULE TRACE (%d rules: %d fired, %d passed, %d skipped", len(result.Trace), counts[types.TraceFired], counts[types.TracePassed], counts[types.TraceSkipped])
+	if n := counts[types.TraceError]; n > 0 {
+		fmt.Printf(", %d failed", n)
+	}
+	fmt.Printf("):\n")
+	for _, t := range result.Trace {
+		fmt.Printf(" %s %s %s: %s", traceIcon(t.Outcome), t.RuleID, t.Outcome, t.Reason)
+		if t.Silenced > 0 {
+			fmt.Printf(" (%d of %d silenced)", t.Silenced, t.Findings)
+		}
+		fmt.Println()
+		if len(t.Inputs) > 0 {
+			inputs := make([]string, 0, len(t.Inputs))
+			for _, f := range t.Inputs {
+				inputs = append(inputs, f.Key+"="+f.Value)
+			}
+			fmt.Printf("    Inputs: %s\n", strings.Join(inputs, ", "))
+		}
+	}
+}
+
+func traceIcon(o types.TraceOutcome) string {
+	switch o {
+	case types.TraceFired:
+		return "❌"
+	case types.TracePassed:
+		return "✓"
+	case types.TraceError:
+		return "⚠"
+	default:
+		return "-"
+	}
 }
 
 // PrintJSON renders the full result as JSON.
@@ -71,3 +352,130 @@ func PrintJSON(result *types.DiagnosticResult) {
 	enc.SetIndent("", "  ")
 	enc.Encode(result)
 }
+
+// PrintFleet renders one line per Dataset of a fleet diagnosis.
+func PrintFleet(results []*types.DiagnosticResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tDATASET\tRUNTIME\tHEALTH\tSCORE\tCRITICAL\tWARNINGS\tROOT CAUSE")
+	for _, r := range results {
+		g := r.ResourceGraph
+		runtime := "<Missing>"
+		if g.Runtime != nil {
+			runtime = g.Runtime.Type
+		}
+		health := "✓ Healthy"
+		if !r.IsHealthy {
+			health = "❌ Unhealthy"
+		}
+		crit, warn := 0, 0
+		var roots []string
+		seen := make(map[string]bool)
+		for _, h := range r.FailureHints {
+			switch h.Severity {
+			case types.SeverityCritical:
+				crit++
+			case types.SeverityWarning:
+				warn++
+			}
+			if h.RootCause && !seen[h.ID] {
+				seen[h.ID] = true
+				roots = append(roots, h.ID)
+			}
+		}
+		rootCause := "-"
+		if len(roots) > 0 {
+			rootCause = strings.Join(roots, ", ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\t%s\n", g.Dataset.Namespace, g.Dataset.Name, runtime, health, r.Score, crit, warn, rootCause)
+	}
+	w.Flush()
+
+	// Root causes of the unhealthy Datasets; `inspect dataset` shows the full report.
+	for _, r := range results {
+		if r.IsHealthy {
+			continue
+		}
+		fmt.Printf("\n%s/%s:\n", r.ResourceGraph.Dataset.Namespace, r.ResourceGraph.Dataset.Name)
+		for _, h := range r.FailureHints {
+			if h.RootCause {
+				printHint(h)
+			}
+		}
+	}
+}
+
+// PrintFleetJSON renders the results of a fleet diagnosis as a JSON array.
+func PrintFleetJSON(results []*types.DiagnosticResult) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(results)
+}
+
+// PrintRules renders one line per rule of the catalog.
+func PrintRules(catalog []diagnose.Metadata) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "ID\tSEVERITY\tCOMPONENT\tTITLE")
+	for _, m := range catalog {
+		title := m.Title
+		if m.Fleet {
+			title += " (fleet)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.ID, orDash(m.Severity), orDash(m.Component), orDash(title))
+	}
+	w.Flush()
+}
+
+// PrintRuleExplain renders everything the catalog knows about one rule.
+func PrintRuleExplain(m diagnose.Metadata) {
+	if m.Title != "" {
+		fmt.Printf("%s: %s\n", m.ID, m.Title)
+	} else {
+		fmt.Printf("%s\n", m.ID)
+	}
+	fmt.Printf("Severity:  %s\n", orDash(m.Severity))
+	fmt.Printf("Component: %s\n", orDash(m.Component))
+	if m.Fleet {
+		fmt.Printf("Scope:     Fleet (inspect datasets); finds nothing for a single Dataset\n")
+	}
+	if len(m.Runtimes) > 0 {
+		fmt.Printf("Runtimes:  %s\n", strings.Join(m.Runtimes, ", "))
+	}
+	if len(m.CausedBy) > 0 {
+		fmt.Printf("Caused by: %s\n", strings.Join(m.CausedBy, ", "))
+	}
+	if m.Description != "" {
+		fmt.Printf("\n%s\n", m.Description)
+	}
+	if m.Checks != "" {
+		fmt.Printf("\nCHECKS:\n  %s\n", m.Checks)
+	}
+	if len(m.Causes) > 0 {
+		fmt.Printf("\nTYPICAL CAUSES:\n")
+		for _, c := range m.Causes {
+			fmt.Printf("  - %s\n", c)
+		}
+	}
+	if len(m.Remediation) > 0 {
+		fmt.Printf("\nREMEDIATION:\n")
+		for i, step := range m.Remediation {
+			fmt.Printf("  %d. %s\n", i+1, step)
+		}
+	}
+	if len(m.Related) > 0 {
+		fmt.Printf("\nRELATED: %s\n", strings.Join(m.Related, ", "))
+	}
+}
+
+// PrintRulesJSON renders catalog entries as JSON.
+func PrintRulesJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -1,7 +1,11 @@
 package scenarios
 
 import (
+	"time"
+
 	"github.com/fluid-cloudnative/fluid-introspector/fluid-introspector/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // Scenario represents a predefined mock scenario.
@@ -9,6 +13,7 @@ type Scenario struct {
 	Name        string
 	Description string
 	Graph       *types.ResourceGraph
+	PodLogs     map[string]string // Sampled logs by pod name, as collected with --logs
 }
 
 // Get finds a scenario by name. Returns nil if not found.
@@ -21,6 +26,21 @@ func Get(name string) *Scenario {
 	return nil
 }
 
+// Names lists the available scenario names in declaration order.
+func Names() []string {
+	names := make([]string, 0, len(All))
+	for _, s := range All {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+// mockNow is the fixed snapshot time used by time-aware scenarios, keeping their output deterministic.
+var mockNow = time.Date(2026, time.January, 1, 10, 0, 0, 0, time.UTC)
+
+// Now is the clock of mock mode: it always returns the scenarios' snapshot time.
+func Now() time.Time { return mockNow }
+
 // All scenarios.
 var All = []Scenario{
 	{
@@ -58,8 +78,14 @@ var All = []Scenario{
 				Name:   "demo-data",
 				Type:   "AlluxioRuntime",
 				Master: &types.ComponentInfo{Name: "demo-data-master", Replicas: 1, Ready: 1, State: "Ready"},
-				Worker: &types.ComponentInfo{Name: "demo-data-worker", Replicas: 3, Ready: 2, State: "PartialReady"}, // Trigger WORKER_PARTIALLY_READY
-				Fuse:   &types.ComponentInfo{Name: "demo-data-fuse", Replicas: 5, Ready: 5, State: "Ready"},
+				Worker: &types.ComponentInfo{Name: "demo-data-worker", Replicas: 3, Ready: 2, State: "PartialReady", // Trigger WORKER_PARTIALLY_READY
+					Pods: []types.PodInfo{
+						{Name: "demo-data-worker-0", Status: "Running", Ready: true, Node: "node-1", Age: "10m"},
+						{Name: "demo-data-worker-1", Status: "Running", Ready: true, Node: "node-2", Age: "10m"},
+						{Name: "demo-data-worker-2", Status: "CrashLoopBackOff", Node: "node-3", Age: "5m", Restarts: 5},
+					},
+				},
+				Fuse: &types.ComponentInfo{Name: "demo-data-fuse", Replicas: 5, Ready: 5, State: "Ready"},
 			},
 			Infrastructure: &types.InfrastructureInfo{
 				PVC: &types.PVCInfo{Name: "demo-data", Status: "Bound"},
@@ -92,12 +118,357 @@ var All = []Scenario{
 				Name:   "demo-data",
 				Type:   "JindoRuntime",
 				Master: &types.ComponentInfo{Name: "demo-data-master", Replicas: 1, Ready: 0}, // Fail Master
-				Worker: &types.ComponentInfo{Name: "demo-data-worker", Replicas: 3, Ready: 1}, // Fail Worker
-				Fuse:   &types.ComponentInfo{Name: "demo-data-fuse", Replicas: 2, Ready: 2},   // Fuse OK
+				Worker: &types.ComponentInfo{Name: "demo-data-worker", Replicas: 3, Ready: 1, // Fail Worker
+					Pods: []types.PodInfo{
+						{Name: "demo-data-worker-0", Status: "Running", Ready: true, Node: "node-1", Age: "1h"},
+						{Name: "demo-data-worker-1", Status: "CrashLoopBackOff", Node: "node-2", Age: "1h", Restarts: 7,
+							Containers: []types.ContainerInfo{{
+								Name: "worker", Image: "registry.example.com/jindofs:6.2.0", RestartCount: 7, MemoryLimit: "4Gi",
+								State:     &corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+								LastState: &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+							}},
+						},
+						{Name: "demo-data-worker-2", Status: "ImagePullBackOff", Node: "node-3", Age: "20m",
+							Containers: []types.ContainerInfo{{
+								Name: "worker", Image: "registry.example.com/jindofs:6.2.1",
+								State: &corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+							}},
+						},
+					},
+				},
+				Fuse: &types.ComponentInfo{Name: "demo-data-fuse", Replicas: 2, Ready: 2}, // Fuse OK
+			},
+			Infrastructure: &types.InfrastructureInfo{
+				PVC: &types.PVCInfo{Name: "demo-data", Status: "Bound"},
+			},
+		},
+	},
+	{
+		Name:        "initializing",
+		Description: "Dataset created a minute ago: components still starting, PVC stuck Pending past its grace period.",
+		Graph: &types.ResourceGraph{
+			ObservedAt: mockNow,
+			Dataset: &types.DatasetInfo{
+				Name:              "demo-data",
+				Status:            "NotBound",
+				Phase:             "NotReady",
+				CreationTimestamp: mockNow.Add(-time.Minute),
+			},
+			Runtime: &types.RuntimeInfo{
+				Name:              "demo-data",
+				Type:              "AlluxioRuntime",
+				CreationTimestamp: mockNow.Add(-time.Minute),
+				Master:            &types.ComponentInfo{Name: "demo-data-master", Replicas: 1, Ready: 0, State: "NotReady", CreationTimestamp: mockNow.Add(-50 * time.Second)}, // Info: still starting
+				Worker:            &types.ComponentInfo{Name: "demo-data-worker", Replicas: 3, Ready: 0, State: "NotReady", CreationTimestamp: mockNow.Add(-50 * time.Second)},
+			},
+			Infrastructure: &types.InfrastructureInfo{
+				PVC: &types.PVCInfo{Name: "demo-data", Status: "Pending", CreationTimestamp: mockNow.Add(-10 * time.Minute)}, // Past grace: Critical
+			},
+		},
+	},
+	{
+		Name:        "silenced",
+		Description: "Fuse is down on purpose; the team acknowledged it through the silence annotation.",
+		Graph: &types.ResourceGraph{
+			ObservedAt: mockNow,
+			Dataset: &types.DatasetInfo{
+				Name:   "demo-data",
+				Status: "Bound",
+				Phase:  "Bound",
+				Silences: []types.Silence{
+					{RuleID: "FUSE_MISSING", Expires: mockNow.Add(7 * 24 * time.Hour), Reason: "Fuse node pool is being migrated"},
+				},
+			},
+			Runtime: &types.RuntimeInfo{
+				Name:   "demo-data",
+				Type:   "AlluxioRuntime",
+				Master: &types.ComponentInfo{Name: "demo-data-master", Replicas: 1, Ready: 1, State: "Ready"},
+				Worker: &types.ComponentInfo{Name: "demo-data-worker", Replicas: 3, Ready: 3, State: "Ready"},
+				Fuse:   &types.ComponentInfo{Name: "demo-data-fuse", Replicas: 5, Ready: 0, State: "NotReady"}, // Silenced FUSE_MISSING
+			},
+			Infrastructure: &types.InfrastructureInfo{
+				PVC: &types.PVCInfo{Name: "demo-data", Status: "Bound"},
+			},
+		},
+	},
+	{
+		Name:        "unschedulable",
+		Description: "Two workers stay Pending: they request more memory than any schedulable node has.",
+		Graph: &types.ResourceGraph{
+			ObservedAt: mockNow,
+			Dataset:    &types.DatasetInfo{Name: "demo-data", Namespace: "default", Status: "Bound", Phase: "Bound"},
+			Runtime: &types.RuntimeInfo{
+				Name:   "demo-data",
+				Type:   "AlluxioRuntime",
+				Master: &types.ComponentInfo{Name: "demo-data-master", Replicas: 1, Ready: 1, State: "Ready"},
+				Worker: &types.ComponentInfo{Name: "demo-data-worker", Replicas: 3, Ready: 1, State: "PartialReady",
+					Pods: []types.PodInfo{
+						{Name: "demo-data-worker-0", Status: "Running", Phase: "Running", Ready: true, Node: "node-1", Age: "2h", Requests: workerRequests},
+						{Name: "demo-data-worker-1", Status: "Pending", Phase: "Pending", Age: "30m", CreationTimestamp: mockNow.Add(-30 * time.Minute), Requests: workerRequests,
+							Unschedulable: "0/4 nodes are available: 1 node(s) were unschedulable, 3 Insufficient memory. preemption: 0/4 nodes are available: 4 No preemption victims found for incoming pod."},
+						{Name: "demo-data-worker-2", Status: "Pending", Phase: "Pending", Age: "30m", CreationTimestamp: mockNow.Add(-30 * time.Minute), Requests: workerRequests},
+					},
+				},
+				Fuse: &types.ComponentInfo{Name: "demo-data-fuse", Replicas: 1, Ready: 1, State: "Ready"},
+			},
+			Infrastructure: &types.InfrastructureInfo{
+				PVC: &types.PVCInfo{Name: "demo-data", Status: "Bound"},
+			},
+			Nodes: []types.NodeInfo{
+				{Name: "node-1", Ready: true, Allocatable: memory("30Gi")},
+				{Name: "node-2", Ready: true, Allocatable: memory("14Gi")},
+				{Name: "node-3", Ready: true, Allocatable: memory("14Gi")},
+				{Name: "node-4", Ready: true, Unschedulable: true, Allocatable: memory("62Gi")},
+			},
+			Events: []types.EventInfo{
+				{Kind: "Pod", Name: "demo-data-worker-2", Type: "Warning", Reason: "FailedScheduling", Count: 12, LastTimestamp: mockNow.Add(-time.Minute),
+					Message: "0/4 nodes are available: 1 node(s) were unschedulable, 3 Insufficient memory. preemption: 0/4 nodes are available: 4 No preemption victims found for incoming pod."},
+			},
+		},
+	},
+	{
+		Name:        "cache-overcommit",
+		Description: "The MEM cache level is larger than the worker memory limit; a worker was OOMKilled and its node is under DiskPressure.",
+		Graph: &types.ResourceGraph{
+			ObservedAt: mockNow,
+			Dataset:    &types.DatasetInfo{Name: "demo-data", Namespace: "default", Status: "Bound", Phase: "Bound"},
+			Runtime: &types.RuntimeInfo{
+				Name: "demo-data",
+				Type: "AlluxioRuntime",
+				TieredStore: []types.TieredStoreLevel{
+					{MediumType: "MEM", Paths: []string{"/dev/shm"}, Quota: resource.MustParse("8Gi")},
+					{MediumType: "SSD", Paths: []string{"/mnt/ssd1", "/mnt/ssd2"}, Quota: resource.MustParse("200Gi")},
+				},
+				Master: &types.ComponentInfo{Name: "demo-data-master", Replicas: 1, Ready: 1, State: "Ready"},
+				Worker: &types.ComponentInfo{Name: "demo-data-worker", Replicas: 2, Ready: 2, State: "Ready",
+					Pods: []types.PodInfo{
+						{Name: "demo-data-worker-0", Status: "Running", Ready: true, Node: "node-1", Restarts: 2, Containers: []types.ContainerInfo{
+							{Name: "alluxio-worker", Ready: true, RestartCount: 2, MemoryLimit: "4Gi",
+								LastState: &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}},
+							{Name: "alluxio-job-worker", Ready: true, MemoryLimit: "1Gi"},
+						}},
+						{Name: "demo-data-worker-1", Status: "Running", Ready: true, Node: "node-2", Containers: []types.ContainerInfo{
+							{Name: "alluxio-worker", Ready: true, MemoryLimit: "4Gi"},
+							{Name: "alluxio-job-worker", Ready: true, MemoryLimit: "1Gi"},
+						}},
+					},
+				},
+				Fuse: &types.ComponentInfo{Name: "demo-data-fuse", Replicas: 2, Ready: 2, State: "Ready"},
+			},
+			Infrastructure: &types.InfrastructureInfo{
+				PVC: &types.PVCInfo{Name: "demo-data", Status: "Bound"},
+			},
+			Nodes: []types.NodeInfo{
+				{Name: "node-1", Ready: true, Allocatable: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("15Gi"), corev1.ResourceEphemeralStorage: resource.MustParse("100Gi")}},
+				{Name: "node-2", Ready: true, Pressures: []string{"DiskPressure"}, Allocatable: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("15Gi"), corev1.ResourceEphemeralStorage: resource.MustParse("500Gi")}},
+			},
+		},
+	},
+	{
+		Name:        "drift",
+		Description: "A partial upgrade: the Runtime spec asks for 5 workers on 2.9.2, the worker StatefulSet still runs 3 on 2.9.0.",
+		Graph: &types.ResourceGraph{
+			ObservedAt: mockNow,
+			Dataset:    &types.DatasetInfo{Name: "demo-data", Namespace: "default", Status: "Bound", Phase: "Bound"},
+			Runtime: &types.RuntimeInfo{
+				Name: "demo-data",
+				Type: "AlluxioRuntime",
+				Master: &types.ComponentInfo{Name: "demo-data-master", Replicas: 1, Ready: 1, State: "Ready",
+					Desired: &types.ComponentSpec{Image: "alluxio/alluxio", ImageTag: "2.9.2"},
+					Actual:  &types.ComponentSpec{Replicas: int32Ptr(1), Image: "alluxio/alluxio", ImageTag: "2.9.2"},
+				},
+				Worker: &types.ComponentInfo{Name: "demo-data-worker", Replicas: 3, Ready: 3, State: "Ready",
+					Desired: &types.ComponentSpec{Replicas: int32Ptr(5), Image: "alluxio/alluxio", ImageTag: "2.9.2", Requests: memory("8Gi")},
+					Actual:  &types.ComponentSpec{Replicas: int32Ptr(3), Image: "alluxio/alluxio", ImageTag: "2.9.0", Requests: memory("8Gi")},
+				},
+				Fuse: &types.ComponentInfo{Name: "demo-data-fuse", Replicas: 3, Ready: 3, State: "Ready"},
+			},
+			Infrastructure: &types.InfrastructureInfo{
+				PVC: &types.PVCInfo{Name: "demo-data", Status: "Bound"},
+			},
+		},
+	},
+	{
+		Name:        "version-skew",
+		Description: "After a Fluid upgrade, fuse pods still run the old image and the CSI plugin was not upgraded.",
+		Graph: &types.ResourceGraph{
+			ObservedAt: mockNow,
+			Dataset:    &types.DatasetInfo{Name: "demo-data", Namespace: "default", Status: "Bound", Phase: "Bound"},
+			Runtime: &types.RuntimeInfo{
+				Name: "demo-data",
+				Type: "AlluxioRuntime",
+				Master: &types.ComponentInfo{Name: "demo-data-master", Replicas: 1, Ready: 1, State: "Ready",
+					Images: []string{"alluxio/alluxio:2.9.2"},
+					Pods:   []types.PodInfo{imagePod("demo-data-master-0", "node-1", "alluxio/alluxio:2.9.2")},
+				},
+				Worker: &types.ComponentInfo{Name: "demo-data-worker", Replicas: 2, Ready: 2, State: "Ready",
+					Images: []string{"alluxio/alluxio:2.9.2"},
+					Pods: []types.PodInfo{
+						imagePod("demo-data-worker-0", "node-1", "alluxio/alluxio:2.9.2"),
+						imagePod("demo-data-worker-1", "node-2", "alluxio/alluxio:2.9.2"),
+					},
+				},
+				Fuse: &types.ComponentInfo{Name: "demo-data-fuse", Replicas: 3, Ready: 3, State: "Ready",
+					Images: []string{"alluxio/alluxio-fuse:2.9.0", "alluxio/alluxio-fuse:2.9.2"},
+					Pods: []types.PodInfo{
+						imagePod("demo-data-fuse-4k2x9", "node-1", "alluxio/alluxio-fuse:2.9.0"),
+						imagePod("demo-data-fuse-8hz2m", "node-2", "alluxio/alluxio-fuse:2.9.0"),
+						imagePod("demo-data-fuse-q7nwp", "node-3", "alluxio/alluxio-fuse:2.9.2"),
+					},
+				},
 			},
 			Infrastructure: &types.InfrastructureInfo{
 				PVC: &types.PVCInfo{Name: "demo-data", Status: "Bound"},
 			},
+			Controllers: []types.ControllerInfo{
+				{Name: "alluxioruntime-controller", Kind: "Deployment", Images: []string{"fluidcloudnative/alluxioruntime-controller:v1.0.0"}, Replicas: 1, Ready: 1},
+				{Name: "csi-nodeplugin-fluid", Kind: "DaemonSet", Images: []string{"registry.k8s.io/sig-storage/csi-node-driver-registrar:v2.9.0", "fluidcloudnative/fluid-csi:v0.9.1"}, Replicas: 3, Ready: 3},
+				{Name: "dataset-controller", Kind: "Deployment", Images: []string{"fluidcloudnative/dataset-controller:v1.0.0"}, Replicas: 1, Ready: 1},
+				{Name: "fluid-webhook", Kind: "Deployment", Images: []string{"fluidcloudnative/fluid-webhook:v1.0.0"}, Replicas: 1, Ready: 1},
+			},
 		},
 	},
+	{
+		Name:        "on-demand-fuse",
+		Description: "An on-demand fuse runs on one node; a training pod was placed on a node the CSI plugin never labelled.",
+		Graph: &types.ResourceGraph{
+			ObservedAt: mockNow,
+			Dataset:    &types.DatasetInfo{Name: "demo-data", Namespace: "default", Status: "Bound", Phase: "Bound"},
+			Runtime: &types.RuntimeInfo{
+				Name:   "demo-data",
+				Type:   "JuiceFSRuntime",
+				Worker: &types.ComponentInfo{Name: "demo-data-worker", Replicas: 1, Ready: 1, State: "Ready"},
+				Fuse: &types.ComponentInfo{Name: "demo-data-fuse", Replicas: 1, Ready: 1, State: "Ready",
+					NodeSelector: map[string]string{"fluid.io/f-default-demo-data": "true"},
+					Pods: []types.PodInfo{
+						{Name: "demo-data-fuse-x8k2p", Status: "Running", Phase: "Running", Ready: true, Node: "node-1", Age: "2h"},
+					},
+				},
+			},
+			Infrastructure: &types.InfrastructureInfo{
+				PVC: &types.PVCInfo{Name: "demo-data", Status: "Bound"},
+			},
+			Nodes: []types.NodeInfo{
+				{Name: "node-1", Ready: true, Labels: map[string]string{"fluid.io/f-default-demo-data": "true"}},
+				{Name: "node-2", Ready: true},
+				{Name: "node-3", Ready: true},
+			},
+			Consumers: []types.PodInfo{
+				{Name: "train-0", Status: "Running", Phase: "Running", Ready: true, Node: "node-1", Age: "2h"},
+				{Name: "train-1", Status: "ContainerCreating", Phase: "Pending", Node: "node-2", Age: "20m", CreationTimestamp: mockNow.Add(-20 * time.Minute)},
+			},
+		},
+	},
+	{
+		Name:        "juicefs-metaurl",
+		Description: "A JuiceFS Dataset stays unbound: its metaurl reads a key the Secret does not have.",
+		Graph: &types.ResourceGraph{
+			ObservedAt: mockNow,
+			Dataset: &types.DatasetInfo{Name: "demo-data", Namespace: "default", Status: "NotBound", Phase: "NotBound",
+				CreationTimestamp: mockNow.Add(-30 * time.Minute),
+				Mounts: []types.MountInfo{{
+					Name:       "minio",
+					MountPoint: "juicefs:///",
+					OptionKeys: []string{"bucket", "storage"},
+					EncryptOptions: []types.EncryptOption{
+						{Name: "access-key", SecretName: "jfs-secret", SecretKey: "access-key"},
+						{Name: "metaurl", SecretName: "jfs-secret", SecretKey: "metaurl"},
+						{Name: "secret-key", SecretName: "jfs-secret", SecretKey: "secret-key"},
+					},
+				}},
+			},
+			Runtime: &types.RuntimeInfo{
+				Name:              "demo-data",
+				Type:              "JuiceFSRuntime",
+				Phase:             "NotReady",
+				CreationTimestamp: mockNow.Add(-30 * time.Minute),
+			},
+			Secrets: []types.SecretInfo{
+				{Name: "jfs-secret", Found: true, Keys: []string{"access-key", "meta-url", "secret-key"}},
+			},
+			Consumers: []types.PodInfo{},
+		},
+	},
+	{
+		Name:        "log-signatures",
+		Description: "All pods of an Alluxio Dataset are ready, but the master cannot mount its bucket and a fuse lost its mount point.",
+		Graph: &types.ResourceGraph{
+			Dataset: &types.DatasetInfo{Name: "demo-data", Namespace: "default", Status: "Bound", Phase: "Bound"},
+			Runtime: &types.RuntimeInfo{
+				Name:   "demo-data",
+				Type:   "AlluxioRuntime",
+				Master: &types.ComponentInfo{Name: "demo-data-master", Replicas: 1, Ready: 1, State: "Ready", Pods: []types.PodInfo{imagePod("demo-data-master-0", "node-1", "fluidcloudnative/alluxio:2.9.0")}},
+				Worker: &types.ComponentInfo{Name: "demo-data-worker", Replicas: 1, Ready: 1, State: "Ready", Pods: []types.PodInfo{imagePod("demo-data-worker-0", "node-1", "fluidcloudnative/alluxio:2.9.0")}},
+				Fuse: &types.ComponentInfo{Name: "demo-data-fuse", Replicas: 2, Ready: 2, State: "Ready", Pods: []types.PodInfo{
+					imagePod("demo-data-fuse-7xk2p", "node-1", "fluidcloudnative/alluxio-fuse:2.9.0"),
+					imagePod("demo-data-fuse-9bq4d", "node-2", "fluidcloudnative/alluxio-fuse:2.9.0"),
+				}},
+			},
+			Infrastructure: &types.InfrastructureInfo{
+				PVC: &types.PVCInfo{Name: "demo-data", Status: "Bound"},
+			},
+		},
+		PodLogs: map[string]string{
+			"demo-data-master-0": "2026-01-01 09:58:02,114 INFO  AlluxioMasterProcess - Starting Alluxio master.\n" +
+				"2026-01-01 09:58:07,530 ERROR DefaultFileSystemMaster - Failed to mount oss://demo-bucket/train at /: Access Denied (Service: Amazon S3; Status Code: 403)\n" +
+				"2026-01-01 09:58:07,531 INFO  DefaultFileSystemMaster - Retrying mount in 30s\n",
+			"demo-data-worker-0":   "2026-01-01 09:58:05,002 INFO  AlluxioWorkerProcess - Alluxio worker started.\n",
+			"demo-data-fuse-7xk2p": "2026-01-01 09:59:12,870 INFO  AlluxioFuse - Mounted alluxio:/ at /runtime-mnt/alluxio/default/demo-data/alluxio-fuse\n",
+			"demo-data-fuse-9bq4d": "2026-01-01 09:59:40,221 ERROR AlluxioFuse - Failed to stat /runtime-mnt/alluxio/default/demo-data/alluxio-fuse: Transport endpoint is not connected\n",
+		},
+	},
+}
+
+func int32Ptr(n int32) *int32 { return &n }
+
+// imagePod is a ready pod running a single container with image.
+func imagePod(name, node, image string) types.PodInfo {
+	return types.PodInfo{Name: name, Status: "Running", Phase: "Running", Ready: true, Node: node, Age: "3d",
+		Containers: []types.ContainerInfo{{Name: "main", Image: image, Ready: true}}}
+}
+
+// workerRequests are the resource requests of the workers of the unschedulable scenario.
+var workerRequests = memory("16Gi")
+
+func memory(q string) corev1.ResourceList {
+	return corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(q)}
+}
+
+// Fleet is the mock fleet of `inspect datasets`: two teams' Alluxio runtimes share
+// node-2, colliding on worker host ports and cache directories, next to a healthy
+// Jindo runtime.
+var Fleet = []*types.ResourceGraph{
+	fleetDataset("team-a", "imagenet", "AlluxioRuntime", "/mnt/cache/imagenet", []int32{29999, 30000},
+		types.PodInfo{Name: "imagenet-worker-0", Node: "node-1"},
+		types.PodInfo{Name: "imagenet-worker-1", Node: "node-2"},
+	),
+	fleetDataset("team-b", "coco", "AlluxioRuntime", "/mnt/cache", []int32{29999, 30000},
+		types.PodInfo{Name: "coco-worker-0", Node: "node-2"},
+	),
+	fleetDataset("team-c", "logs", "JindoRuntime", "/mnt/disk1/jindo/logs", []int32{8101},
+		types.PodInfo{Name: "logs-worker-0", Node: "node-3"},
+	),
+}
+
+// fleetDataset builds a bound Dataset whose ready workers use the given host ports and cache path.
+func fleetDataset(namespace, name, runtimeType, cachePath string, ports []int32, workers ...types.PodInfo) *types.ResourceGraph {
+	for i := range workers {
+		workers[i].Status, workers[i].Phase, workers[i].Ready = "Running", "Running", true
+		workers[i].HostPorts = ports
+		workers[i].HostPaths = []string{"/etc/localtime", cachePath}
+	}
+	n := int32(len(workers))
+	return &types.ResourceGraph{
+		ObservedAt: mockNow,
+		Dataset:    &types.DatasetInfo{Name: name, Namespace: namespace, Status: "Bound", Phase: "Bound"},
+		Runtime: &types.RuntimeInfo{
+			Name:   name,
+			Type:   runtimeType,
+			Master: &types.ComponentInfo{Name: name + "-master", Replicas: 1, Ready: 1, State: "Ready"},
+			Worker: &types.ComponentInfo{Name: name + "-worker", Replicas: n, Ready: n, State: "Ready", Pods: workers},
+		},
+		Infrastructure: &types.InfrastructureInfo{
+			PVC: &types.PVCInfo{Name: name, Status: "Bound"},
+		},
+	}
 }